@@ -7,4 +7,25 @@ data "hcs_agent_helm_config" "default" {
   aks_cluster_name         = var.aks_cluster_name
   aks_resource_group       = var.aks_resource_group
   expose_gossip_ports      = var.expose_gossip_ports
+  chart_version            = "0.31.1"
+  controller               = true
+
+  mesh_gateway {
+    replicas = 2
+  }
+
+  ingress_gateway {
+    name         = "ingress-gateway"
+    service_type = "LoadBalancer"
+  }
+
+  metrics {
+    prometheus_base_url = "http://prometheus-server.monitoring"
+  }
+
+  overrides = yamlencode({
+    client = {
+      grpc = true
+    }
+  })
 }
\ No newline at end of file
@@ -0,0 +1,11 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+data "hcs_billing_info" "default" {
+  resource_group_name      = var.resource_group_name
+  managed_application_name = var.managed_application_name
+}
+
+output "cost_this_month" {
+  value = data.hcs_billing_info.default.usage_this_month[0].cost
+}
@@ -0,0 +1,12 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+data "hcs_billing_report" "default" {
+  resource_group_name      = var.resource_group_name
+  managed_application_name = var.managed_application_name
+  month                    = "2021/06"
+}
+
+output "total_cost" {
+  value = data.hcs_billing_report.default.total_cost
+}
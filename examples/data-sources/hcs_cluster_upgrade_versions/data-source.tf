@@ -0,0 +1,11 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+data "hcs_cluster_upgrade_versions" "default" {
+  resource_group_name      = var.resource_group_name
+  managed_application_name = var.managed_application_name
+}
+
+output "upgrade_target" {
+  value = data.hcs_cluster_upgrade_versions.default.recommended_version
+}
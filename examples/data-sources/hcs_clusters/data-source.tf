@@ -0,0 +1,15 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+data "hcs_clusters" "production" {
+  location = var.location
+  state    = "RUNNING"
+
+  tags = {
+    environment = "production"
+  }
+}
+
+output "production_cluster_names" {
+  value = data.hcs_clusters.production.clusters[*].cluster_name
+}
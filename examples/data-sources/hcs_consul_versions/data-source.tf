@@ -1,4 +1,9 @@
 # Copyright (c) HashiCorp, Inc.
 # SPDX-License-Identifier: MPL-2.0
 
-data "hcs_consul_versions" "default" {}
\ No newline at end of file
+data "hcs_consul_versions" "default" {}
+
+// Select the newest patch release of Consul 1.9.
+data "hcs_consul_versions" "v1_9" {
+  constraint = "~> 1.9.0"
+}
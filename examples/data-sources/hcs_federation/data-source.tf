@@ -0,0 +1,15 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+data "hcs_federation" "default" {
+  resource_group_name      = var.resource_group_name
+  managed_application_name = var.managed_application_name
+}
+
+output "primary_datacenter" {
+  value = data.hcs_federation.default.primary_datacenter[0].name
+}
+
+output "secondary_datacenters" {
+  value = data.hcs_federation.default.secondary_datacenters[*].name
+}
@@ -0,0 +1,12 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+data "hcs_snapshots" "automatic" {
+  resource_group_name      = var.resource_group_name
+  managed_application_name = var.managed_application_name
+  type                     = "AUTOMATIC"
+}
+
+output "latest_automatic_snapshot_id" {
+  value = one(data.hcs_snapshots.automatic.latest[*].id)
+}
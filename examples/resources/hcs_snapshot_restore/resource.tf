@@ -0,0 +1,15 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+// Note: the snapshot is restored when the resource is created, and restored again
+// whenever one of its arguments (including `triggers`) changes.
+resource "hcs_snapshot_restore" "default" {
+  resource_group_name      = var.resource_group_name
+  managed_application_name = var.managed_application_name
+  snapshot_id              = var.snapshot_id
+  take_snapshot            = true
+
+  triggers = {
+    incident = var.incident_id
+  }
+}
@@ -0,0 +1,18 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+variable "resource_group_name" {
+  type = string
+}
+
+variable "managed_application_name" {
+  type = string
+}
+
+variable "snapshot_id" {
+  type = string
+}
+
+variable "incident_id" {
+  type = string
+}
@@ -6,6 +6,8 @@ package clients
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-07-01/containerservice"
@@ -13,9 +15,12 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-07-01/managedapplications"
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2020-06-01/resources"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
 
 	"github.com/hashicorp/go-azure-helpers/authentication"
 	"github.com/hashicorp/go-azure-helpers/sender"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/hcsmeta"
 )
 
 var (
@@ -33,6 +38,14 @@ type Config struct {
 	// SourceChannel denotes the client (channel) that originated the HCS cluster request.
 	// This is synonymous to a user-agent.
 	SourceChannel string
+
+	// OperationPollConfig configures how async Custom Resource Provider operations are polled.
+	// DefaultOperationPollConfig is used if it is not set.
+	OperationPollConfig OperationPollConfig
+
+	// HCSMetaSource is the URL prefix or local directory of the HCS meta repository.
+	// hcsmeta.DefaultSource is used if it is not set.
+	HCSMetaSource string
 }
 
 // Options are the options passed to the client.
@@ -45,6 +58,12 @@ type Options struct {
 
 	// Config is the provider config which contains HCS specific configuration values.
 	Config Config
+
+	// LocalResourceManagerEndpoint, if set, overrides the Azure Resource Manager endpoint of the environment
+	// with a local server, such as the fake in the fakehcs package. It is only meant for tests and is not
+	// exposed by the provider. Requests to it are not authenticated, so it must be a loopback address,
+	// which ensures that requests are never sent unauthenticated to a remote server.
+	LocalResourceManagerEndpoint string
 }
 
 // Client is used by the provider to make authenticated HTTP requests to Azure.
@@ -67,6 +86,9 @@ type Client struct {
 	// VNet is the client used for Azure Virtual Networks CRUD
 	VNet *network.VirtualNetworksClient
 
+	// Meta is the client used to read the HCS meta repository. Its cache is shared by all resources.
+	Meta *hcsmeta.Client
+
 	// Config is the provider config which contains HCS specific configuration values.
 	Config Config
 
@@ -83,6 +105,10 @@ func Build(ctx context.Context, options Options) (*Client, error) {
 		return nil, err
 	}
 
+	if options.LocalResourceManagerEndpoint != "" {
+		env.ResourceManagerEndpoint = options.LocalResourceManagerEndpoint
+	}
+
 	account, err := NewAzureResourceManagerAccount(ctx, *options.AzureAuthConfig, *env)
 	if err != nil {
 		return nil, fmt.Errorf("unable to build account: %v", err)
@@ -90,20 +116,12 @@ func Build(ctx context.Context, options Options) (*Client, error) {
 
 	client := Client{
 		Account:              account,
+		Meta:                 hcsmeta.NewClient(options.Config.HCSMetaSource, hcsmeta.DefaultCacheTTL),
 		Config:               options.Config,
 		CorrelationRequestID: correlationRequestID(),
 	}
 
-	oauthConfig, err := options.AzureAuthConfig.BuildOAuthConfig(env.ActiveDirectoryEndpoint)
-	if err != nil {
-		return nil, err
-	}
-	if oauthConfig == nil {
-		return nil, fmt.Errorf("unable to configure OAuthConfig for tenant %s", options.AzureAuthConfig.TenantID)
-	}
-
-	send := sender.BuildSender(senderProviderName)
-	auth, err := options.AzureAuthConfig.GetAuthorizationToken(send, oauthConfig, env.TokenAudience)
+	auth, err := buildAuthorizer(options, *env)
 	if err != nil {
 		return nil, err
 	}
@@ -115,25 +133,66 @@ func Build(ctx context.Context, options Options) (*Client, error) {
 	configureAutoRestClient(&managedAppClient.Client, auth, options.ProviderUserAgent)
 	client.ManagedApplication = &managedAppClient
 
-	resourceGroupClient := resources.NewGroupsClient(options.AzureAuthConfig.SubscriptionID)
+	resourceGroupClient := resources.NewGroupsClientWithBaseURI(env.ResourceManagerEndpoint, options.AzureAuthConfig.SubscriptionID)
 	configureAutoRestClient(&resourceGroupClient.Client, auth, options.ProviderUserAgent)
 	client.ResourceGroup = &resourceGroupClient
 
 	customResourceProviderClient := NewCustomResourceProviderClientWithBaseURI(env.ResourceManagerEndpoint, options.AzureAuthConfig.SubscriptionID, options.Config.SourceChannel)
 	configureAutoRestClient(&customResourceProviderClient.Client, auth, options.ProviderUserAgent)
+	if options.Config.OperationPollConfig != (OperationPollConfig{}) {
+		customResourceProviderClient.OperationPollConfig = options.Config.OperationPollConfig
+	}
 	client.CustomResourceProvider = &customResourceProviderClient
 
-	managedClustersClient := containerservice.NewManagedClustersClient(options.AzureAuthConfig.SubscriptionID)
+	managedClustersClient := containerservice.NewManagedClustersClientWithBaseURI(env.ResourceManagerEndpoint, options.AzureAuthConfig.SubscriptionID)
 	configureAutoRestClient(&managedClustersClient.Client, auth, options.ProviderUserAgent)
 	client.ManagedClusters = &managedClustersClient
 
-	vNetClient := network.NewVirtualNetworksClient(options.AzureAuthConfig.SubscriptionID)
+	vNetClient := network.NewVirtualNetworksClientWithBaseURI(env.ResourceManagerEndpoint, options.AzureAuthConfig.SubscriptionID)
 	configureAutoRestClient(&vNetClient.Client, auth, options.ProviderUserAgent)
 	client.VNet = &vNetClient
 
 	return &client, nil
 }
 
+// buildAuthorizer builds the authorizer used for requests to Azure. Requests are not authenticated
+// if the Azure Resource Manager endpoint has been overridden with a local server.
+func buildAuthorizer(options Options, env azure.Environment) (autorest.Authorizer, error) {
+	if options.LocalResourceManagerEndpoint != "" {
+		if err := validateLocalEndpoint(options.LocalResourceManagerEndpoint); err != nil {
+			return nil, err
+		}
+
+		return autorest.NullAuthorizer{}, nil
+	}
+
+	oauthConfig, err := options.AzureAuthConfig.BuildOAuthConfig(env.ActiveDirectoryEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	if oauthConfig == nil {
+		return nil, fmt.Errorf("unable to configure OAuthConfig for tenant %s", options.AzureAuthConfig.TenantID)
+	}
+
+	send := sender.BuildSender(senderProviderName)
+	return options.AzureAuthConfig.GetAuthorizationToken(send, oauthConfig, env.TokenAudience)
+}
+
+// validateLocalEndpoint ensures that the endpoint is the URL of a server on a loopback address.
+func validateLocalEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid local Azure Resource Manager endpoint %q: %v", endpoint, err)
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("local Azure Resource Manager endpoint %q must be a loopback address, since requests to it are not authenticated", endpoint)
+	}
+
+	return nil
+}
+
 // configureAutoRestClient is used to configure an Azure Autorest client with the appropriate User Agent,
 // authorizer, and correlation id etc.
 func configureAutoRestClient(c *autorest.Client, authorizer autorest.Authorizer, providerUserAgent string) {
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/hashicorp/go-azure-helpers/authentication"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_buildAuthorizer(t *testing.T) {
+	tcs := map[string]struct {
+		endpoint    string
+		expectedErr string
+	}{
+		"loopback IPv4 address": {
+			endpoint: "http://127.0.0.1:8080",
+		},
+		"loopback IPv6 address": {
+			endpoint: "http://[::1]:8080",
+		},
+		"localhost": {
+			endpoint: "http://localhost:8080",
+		},
+		"remote host": {
+			endpoint:    "https://management.example.com",
+			expectedErr: `local Azure Resource Manager endpoint "https://management.example.com" must be a loopback address`,
+		},
+		"remote address": {
+			endpoint:    "http://10.0.0.1:8080",
+			expectedErr: `local Azure Resource Manager endpoint "http://10.0.0.1:8080" must be a loopback address`,
+		},
+		"invalid URL": {
+			endpoint:    "http://127.0.0.1:port",
+			expectedErr: `invalid local Azure Resource Manager endpoint "http://127.0.0.1:port"`,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			auth, err := buildAuthorizer(Options{
+				AzureAuthConfig:              &authentication.Config{},
+				LocalResourceManagerEndpoint: tc.endpoint,
+			}, azure.PublicCloud)
+			if tc.expectedErr != "" {
+				r.Error(err)
+				r.Contains(err.Error(), tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+			r.Equal(autorest.NullAuthorizer{}, auth)
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package clients
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+)
+
+// grpcCodeNames maps the canonical gRPC status codes to their names.
+// https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
+var grpcCodeNames = map[int32]string{
+	0:  "OK",
+	1:  "CANCELLED",
+	2:  "UNKNOWN",
+	3:  "INVALID_ARGUMENT",
+	4:  "DEADLINE_EXCEEDED",
+	5:  "NOT_FOUND",
+	6:  "ALREADY_EXISTS",
+	7:  "PERMISSION_DENIED",
+	8:  "RESOURCE_EXHAUSTED",
+	9:  "FAILED_PRECONDITION",
+	10: "ABORTED",
+	11: "OUT_OF_RANGE",
+	12: "UNIMPLEMENTED",
+	13: "INTERNAL",
+	14: "UNAVAILABLE",
+	15: "DATA_LOSS",
+	16: "UNAUTHENTICATED",
+}
+
+// grpcCodeHTTPStatus maps the canonical gRPC status codes to the HTTP status
+// used by the gRPC gateway for the same code.
+var grpcCodeHTTPStatus = map[int32]int{
+	0:  http.StatusOK,
+	1:  499,
+	2:  http.StatusInternalServerError,
+	3:  http.StatusBadRequest,
+	4:  http.StatusGatewayTimeout,
+	5:  http.StatusNotFound,
+	6:  http.StatusConflict,
+	7:  http.StatusForbidden,
+	8:  http.StatusTooManyRequests,
+	9:  http.StatusBadRequest,
+	10: http.StatusConflict,
+	11: http.StatusBadRequest,
+	12: http.StatusNotImplemented,
+	13: http.StatusInternalServerError,
+	14: http.StatusServiceUnavailable,
+	15: http.StatusInternalServerError,
+	16: http.StatusUnauthorized,
+}
+
+// grpcCodeNotFound is the gRPC status code returned when a resource does not exist.
+const grpcCodeNotFound = 5
+
+// CRPErrorDetail is a single detail message attached to a Custom Resource Provider error.
+type CRPErrorDetail struct {
+	// TypeURL identifies the type of the detail message, e.g. 'type.googleapis.com/google.rpc.ErrorInfo'.
+	TypeURL string
+	// Value is the content of the detail message; JSON if the detail was rendered by the gRPC gateway,
+	// base64 encoded protobuf otherwise.
+	Value string
+}
+
+// CRPError is the decoded error returned by a Custom Resource Provider Action or an async operation.
+type CRPError struct {
+	// HTTPStatus is the HTTP status code of the response. For async operations it is derived from the gRPC code.
+	HTTPStatus int
+	// Code is the gRPC status code.
+	Code int32
+	// Message is the error message.
+	Message string
+	// Details are the detail messages attached to the error.
+	Details []CRPErrorDetail
+}
+
+// CodeName returns the name of the gRPC status code, e.g. 'FAILED_PRECONDITION'.
+func (e *CRPError) CodeName() string {
+	if name, ok := grpcCodeNames[e.Code]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("CODE_%d", e.Code)
+}
+
+// Error implements the error interface.
+func (e *CRPError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d): %s", e.CodeName(), e.HTTPStatus, e.Summary())
+}
+
+// Summary returns the error message, falling back to the gRPC code name if the message is empty.
+func (e *CRPError) Summary() string {
+	if e.Message == "" {
+		return e.CodeName()
+	}
+
+	return e.Message
+}
+
+// Detail returns a multi-line description of the gRPC code, HTTP status and detail messages of the error.
+func (e *CRPError) Detail() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "gRPC code: %s (%d)\n", e.CodeName(), e.Code)
+	fmt.Fprintf(&b, "HTTP status: %d", e.HTTPStatus)
+	if e.Message != "" {
+		fmt.Fprintf(&b, "\nMessage: %s", e.Message)
+	}
+	for _, detail := range e.Details {
+		fmt.Fprintf(&b, "\nDetail: %s %s", detail.TypeURL, detail.Value)
+	}
+
+	return b.String()
+}
+
+// IsNotFound determines if the error denotes a resource that does not exist.
+func (e *CRPError) IsNotFound() bool {
+	return e.HTTPStatus == http.StatusNotFound || e.Code == grpcCodeNotFound
+}
+
+// NewCRPErrorFromStatus converts the status of a failed async operation to a CRPError.
+func NewCRPErrorFromStatus(status *models.GoogleRPCStatus) *CRPError {
+	crpErr := &CRPError{
+		HTTPStatus: grpcCodeHTTPStatus[status.Code],
+		Code:       status.Code,
+		Message:    status.Message,
+	}
+	if crpErr.HTTPStatus == 0 {
+		crpErr.HTTPStatus = http.StatusInternalServerError
+	}
+
+	for _, detail := range status.Details {
+		if detail == nil {
+			continue
+		}
+
+		crpErr.Details = append(crpErr.Details, CRPErrorDetail{
+			TypeURL: detail.TypeURL,
+			Value:   base64.StdEncoding.EncodeToString(detail.Value),
+		})
+	}
+
+	return crpErr
+}
+
+// decodeCRPError decodes the body of a failed Custom Resource Provider response.
+// The body is either a GrpcGatewayRuntimeError or a GoogleRPCStatus, optionally
+// wrapped in an 'error' property by Azure. The returned bool is false if the body
+// could not be decoded.
+func decodeCRPError(statusCode int, body []byte) (*CRPError, bool) {
+	var envelope struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil && len(envelope.Error) > 0 && envelope.Error[0] == '{' {
+		body = envelope.Error
+	}
+
+	// Details are decoded separately since the gRPC gateway renders them
+	// as JSON objects with an '@type' property rather than typeUrl and value.
+	var runtimeErr struct {
+		Code    json.Number       `json:"code"`
+		Error   string            `json:"error"`
+		Message string            `json:"message"`
+		Details []json.RawMessage `json:"details"`
+	}
+	if err := json.Unmarshal(body, &runtimeErr); err != nil {
+		return nil, false
+	}
+
+	code, err := runtimeErr.Code.Int64()
+	if err != nil || (code == 0 && runtimeErr.Message == "" && runtimeErr.Error == "") {
+		return nil, false
+	}
+
+	crpErr := &CRPError{
+		HTTPStatus: statusCode,
+		Code:       int32(code),
+		Message:    runtimeErr.Message,
+	}
+	if crpErr.Message == "" {
+		crpErr.Message = runtimeErr.Error
+	}
+
+	for _, raw := range runtimeErr.Details {
+		crpErr.Details = append(crpErr.Details, decodeCRPErrorDetail(raw))
+	}
+
+	return crpErr, true
+}
+
+// decodeCRPErrorDetail decodes a detail message of a gRPC gateway error.
+func decodeCRPErrorDetail(raw json.RawMessage) CRPErrorDetail {
+	var anyDetail models.GoogleProtobufAny
+	if err := json.Unmarshal(raw, &anyDetail); err == nil && anyDetail.TypeURL != "" {
+		return CRPErrorDetail{
+			TypeURL: anyDetail.TypeURL,
+			Value:   base64.StdEncoding.EncodeToString(anyDetail.Value),
+		}
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return CRPErrorDetail{Value: string(raw)}
+	}
+
+	var detail CRPErrorDetail
+	if typeURL, ok := fields["@type"]; ok {
+		_ = json.Unmarshal(typeURL, &detail.TypeURL)
+		delete(fields, "@type")
+	}
+
+	// The remaining fields are marshalled with sorted keys.
+	value, err := json.Marshal(fields)
+	if err != nil {
+		return CRPErrorDetail{Value: string(raw)}
+	}
+	detail.Value = string(value)
+
+	return detail
+}
+
+// withCRPErrorUnlessStatusCode returns a RespondDecorator that emits a CRPError if the response
+// status code is not one of the passed codes and the body can be decoded. Otherwise it behaves
+// like azure.WithErrorUnlessStatusCode.
+func withCRPErrorUnlessStatusCode(codes ...int) autorest.RespondDecorator {
+	return func(r autorest.Responder) autorest.Responder {
+		return autorest.ResponderFunc(func(resp *http.Response) error {
+			err := r.Respond(resp)
+			if err != nil || autorest.ResponseHasStatusCode(resp, codes...) {
+				return err
+			}
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err == nil {
+				resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+				if crpErr, ok := decodeCRPError(resp.StatusCode, body); ok {
+					return crpErr
+				}
+			}
+
+			return azure.WithErrorUnlessStatusCode(codes...)(autorest.ResponderFunc(func(*http.Response) error {
+				return nil
+			})).Respond(resp)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package clients
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+)
+
+func Test_decodeCRPError(t *testing.T) {
+	tcs := map[string]struct {
+		body     string
+		expected *CRPError
+	}{
+		"grpc gateway error": {
+			body: `{"error":"cluster is updating","code":9,"message":"cluster is updating","details":[{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"UPDATING","domain":"hcs"}]}`,
+			expected: &CRPError{
+				HTTPStatus: http.StatusBadRequest,
+				Code:       9,
+				Message:    "cluster is updating",
+				Details: []CRPErrorDetail{
+					{
+						TypeURL: "type.googleapis.com/google.rpc.ErrorInfo",
+						Value:   `{"domain":"hcs","reason":"UPDATING"}`,
+					},
+				},
+			},
+		},
+		"rpc status wrapped by azure": {
+			body: `{"error":{"code":5,"message":"snapshot not found","details":[{"typeUrl":"type.googleapis.com/hcs.Snapshot","value":"aGNz"}]}}`,
+			expected: &CRPError{
+				HTTPStatus: http.StatusBadRequest,
+				Code:       5,
+				Message:    "snapshot not found",
+				Details: []CRPErrorDetail{
+					{
+						TypeURL: "type.googleapis.com/hcs.Snapshot",
+						Value:   "aGNz",
+					},
+				},
+			},
+		},
+		"gateway error without message": {
+			body: `{"error":"internal failure","code":13}`,
+			expected: &CRPError{
+				HTTPStatus: http.StatusBadRequest,
+				Code:       13,
+				Message:    "internal failure",
+			},
+		},
+		"azure error": {
+			body:     `{"error":{"code":"ResourceNotFound","message":"not found"}}`,
+			expected: nil,
+		},
+		"not json": {
+			body:     `Bad Request`,
+			expected: nil,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			crpErr, ok := decodeCRPError(http.StatusBadRequest, []byte(tc.body))
+			r.Equal(tc.expected != nil, ok)
+			r.Equal(tc.expected, crpErr)
+		})
+	}
+}
+
+func Test_NewCRPErrorFromStatus(t *testing.T) {
+	r := require.New(t)
+
+	crpErr := NewCRPErrorFromStatus(&models.GoogleRPCStatus{
+		Code:    9,
+		Message: "a federation requires a Production cluster",
+		Details: []*models.GoogleProtobufAny{
+			{TypeURL: "type.googleapis.com/google.rpc.PreconditionFailure", Value: strfmt.Base64("hcs")},
+			nil,
+		},
+	})
+
+	r.Equal(&CRPError{
+		HTTPStatus: http.StatusBadRequest,
+		Code:       9,
+		Message:    "a federation requires a Production cluster",
+		Details: []CRPErrorDetail{
+			{TypeURL: "type.googleapis.com/google.rpc.PreconditionFailure", Value: "aGNz"},
+		},
+	}, crpErr)
+	r.Equal("FAILED_PRECONDITION (HTTP 400): a federation requires a Production cluster", crpErr.Error())
+	r.Equal("gRPC code: FAILED_PRECONDITION (9)\nHTTP status: 400\nMessage: a federation requires a Production cluster\nDetail: type.googleapis.com/google.rpc.PreconditionFailure aGNz", crpErr.Detail())
+}
+
+func Test_CRPError_IsNotFound(t *testing.T) {
+	r := require.New(t)
+
+	r.True((&CRPError{HTTPStatus: http.StatusNotFound}).IsNotFound())
+	r.True((&CRPError{HTTPStatus: http.StatusBadRequest, Code: 5}).IsNotFound())
+	r.False((&CRPError{HTTPStatus: http.StatusBadRequest, Code: 9}).IsNotFound())
+	r.Equal("CODE_42", (&CRPError{Code: 42}).CodeName())
+}
+
+func Test_withCRPErrorUnlessStatusCode(t *testing.T) {
+	respond := func(statusCode int, body string) error {
+		resp := &http.Response{
+			StatusCode: statusCode,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request:    &http.Request{Method: http.MethodPost},
+		}
+
+		return autorest.Respond(resp, withCRPErrorUnlessStatusCode(http.StatusOK), autorest.ByClosing())
+	}
+
+	t.Run("success", func(t *testing.T) {
+		r := require.New(t)
+
+		r.NoError(respond(http.StatusOK, `{}`))
+	})
+
+	t.Run("decodable error", func(t *testing.T) {
+		r := require.New(t)
+
+		err := respond(http.StatusNotFound, `{"code":5,"message":"cluster not found"}`)
+		r.IsType(&CRPError{}, err)
+		r.Equal(http.StatusNotFound, err.(*CRPError).HTTPStatus)
+		r.True(CustomResourceProviderClient{}.IsCRPErrorAzureNotFound(err))
+	})
+
+	t.Run("azure error", func(t *testing.T) {
+		r := require.New(t)
+
+		err := respond(http.StatusNotFound, `{"error":{"code":"ResourceNotFound","message":"not found"}}`)
+		r.IsType(&azure.RequestError{}, err)
+		r.True(CustomResourceProviderClient{}.IsCRPErrorAzureNotFound(err))
+	})
+}
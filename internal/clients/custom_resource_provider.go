@@ -5,10 +5,10 @@ package clients
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
@@ -27,6 +27,8 @@ type CustomResourceProviderClient struct {
 	// SourceChannel denotes the client (channel) that originated the HCS cluster request.
 	// This is synonymous to a user-agent.
 	SourceChannel string
+	// OperationPollConfig configures how async operations are polled.
+	OperationPollConfig OperationPollConfig
 }
 
 // ConsulConfig represents the Consul config returned on the GetConfig response.
@@ -41,10 +43,11 @@ type ConsulConfig struct {
 // base URI and subscription id.
 func NewCustomResourceProviderClientWithBaseURI(baseURI string, subscriptionID string, sourceChannel string) CustomResourceProviderClient {
 	return CustomResourceProviderClient{
-		Client:         autorest.NewClientWithUserAgent("hcs-custom-resource-provider"),
-		BaseURI:        baseURI,
-		SubscriptionID: subscriptionID,
-		SourceChannel:  sourceChannel,
+		Client:              autorest.NewClientWithUserAgent("hcs-custom-resource-provider"),
+		BaseURI:             baseURI,
+		SubscriptionID:      subscriptionID,
+		SourceChannel:       sourceChannel,
+		OperationPollConfig: DefaultOperationPollConfig,
 	}
 }
 
@@ -90,7 +93,7 @@ func (client CustomResourceProviderClient) CreateRootToken(ctx context.Context,
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&rootToken),
 		autorest.ByClosing())
 
@@ -131,13 +134,53 @@ func (client CustomResourceProviderClient) FetchConsulCluster(ctx context.Contex
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&cluster),
 		autorest.ByClosing())
 
 	return cluster, err
 }
 
+// ListConsulClusters invokes the consulClusters Custom Resource Action.
+func (client CustomResourceProviderClient) ListConsulClusters(ctx context.Context, managedResourceGroupId string) (models.HashicorpCloudConsulamaAmaListClustersResponse, error) {
+	var clusters models.HashicorpCloudConsulamaAmaListClustersResponse
+
+	pathParams := map[string]interface{}{
+		"resourceGroup": autorest.Encode("path", managedResourceGroupId),
+	}
+
+	const APIVersion = "2018-09-01-preview"
+	queryParams := map[string]interface{}{
+		"api-version": APIVersion,
+	}
+
+	preparer := autorest.CreatePreparer(
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.AsGet(),
+		autorest.WithBaseURL(client.BaseURI),
+		autorest.WithPathParameters("/{resourceGroup}/providers/Microsoft.CustomProviders/resourceProviders/public/consulClusters", pathParams),
+		autorest.WithQueryParameters(queryParams))
+
+	req, err := preparer.Prepare((&http.Request{}).WithContext(ctx))
+	if err != nil {
+		return clusters, err
+	}
+
+	var resp *http.Response
+	resp, err = client.Send(req, azure.DoRetryWithRegistration(client.Client))
+	if err != nil {
+		return clusters, err
+	}
+
+	err = autorest.Respond(
+		resp,
+		withCRPErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(&clusters),
+		autorest.ByClosing())
+
+	return clusters, err
+}
+
 // CreateSnapshot invokes the createSnapshot Custom Resource Provider Action
 func (client CustomResourceProviderClient) CreateSnapshot(ctx context.Context, managedResourceGroupID,
 	resourceGroupName, snapshotName string) (models.HashicorpCloudConsulamaAmaCreateSnapshotResponse, error) {
@@ -162,7 +205,7 @@ func (client CustomResourceProviderClient) CreateSnapshot(ctx context.Context, m
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&snapshotResponse),
 		autorest.ByClosing())
 
@@ -194,7 +237,7 @@ func (client CustomResourceProviderClient) GetSnapshot(ctx context.Context, mana
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&snapshotResponse),
 		autorest.ByClosing())
 
@@ -226,7 +269,7 @@ func (client CustomResourceProviderClient) DeleteSnapshot(ctx context.Context, m
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&snapshotResponse),
 		autorest.ByClosing())
 
@@ -259,13 +302,80 @@ func (client CustomResourceProviderClient) RenameSnapshot(ctx context.Context, m
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&snapshotResponse),
 		autorest.ByClosing())
 
 	return snapshotResponse, err
 }
 
+// RestoreSnapshot invokes the restoreSnapshot Custom Resource Provider Action
+func (client CustomResourceProviderClient) RestoreSnapshot(ctx context.Context, managedResourceGroupID, resourceGroupName,
+	snapshotID string, takeSnapshot bool) (models.HashicorpCloudConsulamaAmaRestoreSnapshotResponse, error) {
+
+	var restoreResponse models.HashicorpCloudConsulamaAmaRestoreSnapshotResponse
+
+	body := models.HashicorpCloudConsulamaAmaRestoreSnapshotRequest{
+		ResourceGroup:  resourceGroupName,
+		SnapshotID:     snapshotID,
+		SubscriptionID: client.SubscriptionID,
+		TakeSnapshot:   models.HashicorpCloudConsulamaAmaBooleanFALSE,
+	}
+	if takeSnapshot {
+		body.TakeSnapshot = models.HashicorpCloudConsulamaAmaBooleanTRUE
+	}
+
+	req, err := client.customActionPreparer(ctx, managedResourceGroupID, "restoreSnapshot", body)
+	if err != nil {
+		return restoreResponse, err
+	}
+
+	var resp *http.Response
+	resp, err = client.Send(req, azure.DoRetryWithRegistration(client.Client))
+	if err != nil {
+		return restoreResponse, err
+	}
+
+	err = autorest.Respond(
+		resp,
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		autorest.ByUnmarshallingJSON(&restoreResponse),
+		autorest.ByClosing())
+
+	return restoreResponse, err
+}
+
+// ListSnapshots invokes the listSnapshots Custom Resource Provider Action
+func (client CustomResourceProviderClient) ListSnapshots(ctx context.Context, managedResourceGroupID,
+	resourceGroupName string) (models.HashicorpCloudConsulamaAmaListSnapshotsResponse, error) {
+
+	var snapshotsResponse models.HashicorpCloudConsulamaAmaListSnapshotsResponse
+
+	body := models.HashicorpCloudConsulamaAmaListSnapshotsRequest{
+		ResourceGroup:  resourceGroupName,
+		SubscriptionID: client.SubscriptionID,
+	}
+
+	req, err := client.customActionPreparer(ctx, managedResourceGroupID, "listSnapshots", body)
+	if err != nil {
+		return snapshotsResponse, err
+	}
+
+	var resp *http.Response
+	resp, err = client.Send(req, azure.DoRetryWithRegistration(client.Client))
+	if err != nil {
+		return snapshotsResponse, err
+	}
+
+	err = autorest.Respond(
+		resp,
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		autorest.ByUnmarshallingJSON(&snapshotsResponse),
+		autorest.ByClosing())
+
+	return snapshotsResponse, err
+}
+
 // ListUpgradeVersions invokes the listConsulUpgradeVersions Custom Resource Provider Action.
 func (client CustomResourceProviderClient) ListUpgradeVersions(ctx context.Context, managedResourceGroupId string) (models.HashicorpCloudConsulamaAmaListConsulUpgradeVersionsResponse, error) {
 	var upgradeVersions models.HashicorpCloudConsulamaAmaListConsulUpgradeVersionsResponse
@@ -286,7 +396,7 @@ func (client CustomResourceProviderClient) ListUpgradeVersions(ctx context.Conte
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&upgradeVersions),
 		autorest.ByClosing())
 
@@ -315,7 +425,7 @@ func (client CustomResourceProviderClient) UpdateCluster(ctx context.Context, ma
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&updateResponse),
 		autorest.ByClosing())
 
@@ -344,7 +454,7 @@ func (client CustomResourceProviderClient) GetFederation(ctx context.Context, ma
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&federationResponse),
 		autorest.ByClosing())
 
@@ -373,7 +483,7 @@ func (client CustomResourceProviderClient) CreateFederationToken(ctx context.Con
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&federationTokenResponse),
 		autorest.ByClosing())
 
@@ -402,7 +512,7 @@ func (client CustomResourceProviderClient) GetConsulConfig(ctx context.Context,
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&configResponse),
 		autorest.ByClosing())
 
@@ -417,6 +527,66 @@ func (client CustomResourceProviderClient) GetConsulConfig(ctx context.Context,
 	return config, err
 }
 
+// GetBillingInfo invokes the billingInfo Custom Resource Provider Action
+func (client CustomResourceProviderClient) GetBillingInfo(ctx context.Context, managedResourceGroupID string) (models.HashicorpCloudConsulamaAmaGetBillingInfoResponse, error) {
+	var billingInfoResponse models.HashicorpCloudConsulamaAmaGetBillingInfoResponse
+
+	body := models.HashicorpCloudConsulamaAmaGetBillingInfoRequest{
+		ResourceGroup:  managedResourceGroupID,
+		SubscriptionID: client.SubscriptionID,
+	}
+
+	req, err := client.customActionPreparer(ctx, managedResourceGroupID, "billingInfo", body)
+	if err != nil {
+		return billingInfoResponse, err
+	}
+
+	var resp *http.Response
+	resp, err = client.Send(req, azure.DoRetryWithRegistration(client.Client))
+	if err != nil {
+		return billingInfoResponse, err
+	}
+
+	err = autorest.Respond(
+		resp,
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		autorest.ByUnmarshallingJSON(&billingInfoResponse),
+		autorest.ByClosing())
+
+	return billingInfoResponse, err
+}
+
+// GetBillingReport invokes the billingReport Custom Resource Provider Action.
+// The month must be of the format YYYY/MM.
+func (client CustomResourceProviderClient) GetBillingReport(ctx context.Context, managedResourceGroupID string, month string) (models.HashicorpCloudConsulamaAmaGetBillingReportResponse, error) {
+	var billingReportResponse models.HashicorpCloudConsulamaAmaGetBillingReportResponse
+
+	body := models.HashicorpCloudConsulamaAmaGetBillingReportRequest{
+		Month:          month,
+		ResourceGroup:  managedResourceGroupID,
+		SubscriptionID: client.SubscriptionID,
+	}
+
+	req, err := client.customActionPreparer(ctx, managedResourceGroupID, "billingReport", body)
+	if err != nil {
+		return billingReportResponse, err
+	}
+
+	var resp *http.Response
+	resp, err = client.Send(req, azure.DoRetryWithRegistration(client.Client))
+	if err != nil {
+		return billingReportResponse, err
+	}
+
+	err = autorest.Respond(
+		resp,
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		autorest.ByUnmarshallingJSON(&billingReportResponse),
+		autorest.ByClosing())
+
+	return billingReportResponse, err
+}
+
 // GetOperation invokes the operation Custom Resource Provider Action
 func (client CustomResourceProviderClient) GetOperation(ctx context.Context, managedResourceGroupID,
 	resourceGroupName, operationID string) (models.HashicorpCloudConsulamaAmaGetOperationResponse, error) {
@@ -442,51 +612,46 @@ func (client CustomResourceProviderClient) GetOperation(ctx context.Context, man
 
 	err = autorest.Respond(
 		resp,
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		withCRPErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
 		autorest.ByUnmarshallingJSON(&opResp),
 		autorest.ByClosing())
 
 	return opResp, err
 }
 
-// PollOperation will poll the operation Custom Resource Provider Action
-// endpoint every pollInterval seconds until the operation state is DONE
-// or the context cancels the request.
-func (client CustomResourceProviderClient) PollOperation(ctx context.Context, operationID, managedResourceGroupID, managedAppName string,
-	pollInterval int) error {
+// IsCRPErrorAzureNotFound determines if the the error returned from a Custom Resource Provider Action
+// was a 404 not found.
+func (_ CustomResourceProviderClient) IsCRPErrorAzureNotFound(err error) bool {
+	if crpErr, ok := err.(*CRPError); ok {
+		return crpErr.IsNotFound()
+	}
 
-	ticker := time.NewTicker(time.Duration(pollInterval) * time.Second)
-	defer ticker.Stop()
+	azErr, ok := err.(*azure.RequestError)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("context cancelled")
-		case <-ticker.C:
-			resp, err := client.GetOperation(ctx, managedResourceGroupID, managedAppName, operationID)
-			if err != nil {
-				return err
-			}
+	return ok && azErr.StatusCode == 404
+}
 
-			if resp.Operation.State != models.HashicorpCloudConsulamaAmaOperationStateDONE {
-				continue
-			}
+// DecodeConsulConfig decodes the Base64 encoded Consul config and CA file of a cluster,
+// as returned in the consulConfigFile and consulCaFile cluster properties, into a ConsulConfig.
+func DecodeConsulConfig(encodedConfig, encodedCAFile string) (*ConsulConfig, error) {
+	rawConfig, err := base64.StdEncoding.DecodeString(encodedConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode Consul config: %+v", err)
+	}
 
-			if resp.Operation.Error != nil {
-				return fmt.Errorf("an error occurred in an aysnc operation; code: %d", resp.Operation.Error.Code)
-			}
+	caFile, err := base64.StdEncoding.DecodeString(encodedCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode Consul CA file: %+v", err)
+	}
 
-			return nil
-		}
+	config, err := unmarshalConsulConfig(string(rawConfig))
+	if err != nil {
+		return nil, err
 	}
-}
 
-// IsCRPErrorAzureNotFound determines if the the error returned from a Custom Resource Provider Action
-// was a 404 not found.
-func (_ CustomResourceProviderClient) IsCRPErrorAzureNotFound(err error) bool {
-	azErr, ok := err.(*azure.RequestError)
+	config.CaFile = string(caFile)
 
-	return ok && azErr.StatusCode == 404
+	return config, nil
 }
 
 // unmarshalConsulConfig will unmarshal the passed in string c,
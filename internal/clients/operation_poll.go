@@ -0,0 +1,222 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+)
+
+// OperationPollConfig configures how async Custom Resource Provider operations are polled.
+type OperationPollConfig struct {
+	// Interval is the delay before the first poll of an operation.
+	Interval time.Duration
+
+	// MaxInterval is the maximum delay between two polls of an operation.
+	MaxInterval time.Duration
+
+	// Multiplier is the factor the delay is increased by after each poll.
+	Multiplier float64
+
+	// Jitter is the fraction by which each delay is randomly increased or decreased.
+	Jitter float64
+
+	// MaxTransientFailures is the number of consecutive transient polling errors that are
+	// tolerated before polling is aborted.
+	MaxTransientFailures int
+}
+
+// DefaultOperationPollConfig is the OperationPollConfig used if none is configured.
+var DefaultOperationPollConfig = OperationPollConfig{
+	Interval:             10 * time.Second,
+	MaxInterval:          time.Minute,
+	Multiplier:           1.5,
+	Jitter:               0.2,
+	MaxTransientFailures: 3,
+}
+
+// nextInterval returns the delay to wait after a poll that was preceded by the passed delay.
+func (c OperationPollConfig) nextInterval(current time.Duration) time.Duration {
+	next := time.Duration(float64(current) * c.Multiplier)
+	if next < c.Interval {
+		next = c.Interval
+	}
+	if next > c.MaxInterval {
+		next = c.MaxInterval
+	}
+
+	return next
+}
+
+// withJitter randomly increases or decreases the delay by up to the configured jitter fraction.
+// The random value r must be in [0, 1).
+func (c OperationPollConfig) withJitter(delay time.Duration, r float64) time.Duration {
+	return time.Duration(float64(delay) * (1 + c.Jitter*(2*r-1)))
+}
+
+// Backoff returns a func which waits for the delay before the next poll of a long running process
+// other than a Custom Resource Provider operation, e.g. the deletion of an Azure resource. The delay
+// starts at Interval and grows exponentially with jitter up to MaxInterval with each call. The func
+// returns the error of the context if it is done before the delay has elapsed.
+func (c OperationPollConfig) Backoff() func(ctx context.Context) error {
+	var interval time.Duration
+
+	return func(ctx context.Context) error {
+		interval = c.nextInterval(interval)
+
+		timer := time.NewTimer(c.withJitter(interval, rand.Float64()))
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
+// OperationTimeoutError is returned when the context of an operation poll is done before the operation is.
+type OperationTimeoutError struct {
+	// OperationID is the ID of the polled operation.
+	OperationID string
+
+	// LastState is the last observed state of the operation. Empty if the state was never observed.
+	LastState models.HashicorpCloudConsulamaAmaOperationState
+
+	// Err is the error of the context.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *OperationTimeoutError) Error() string {
+	return fmt.Sprintf("timed out waiting for operation %q to complete; last observed state: %s: %v", e.OperationID, stateOrUnknown(e.LastState), e.Err)
+}
+
+// Unwrap returns the error of the context.
+func (e *OperationTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// PollOperation will poll the operation Custom Resource Provider Action
+// endpoint until the operation state is DONE or the context cancels the request.
+// The delay between polls grows exponentially with jitter as configured by the
+// OperationPollConfig of the client, and up to MaxTransientFailures consecutive
+// transient errors are tolerated.
+func (client CustomResourceProviderClient) PollOperation(ctx context.Context, operationID, managedResourceGroupID, managedAppName string) error {
+	config := client.OperationPollConfig
+
+	var lastState models.HashicorpCloudConsulamaAmaOperationState
+	transientFailures := 0
+	interval := config.Interval
+
+	timer := time.NewTimer(config.withJitter(interval, rand.Float64()))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return &OperationTimeoutError{
+				OperationID: operationID,
+				LastState:   lastState,
+				Err:         ctx.Err(),
+			}
+		case <-timer.C:
+		}
+
+		resp, err := client.GetOperation(ctx, managedResourceGroupID, managedAppName, operationID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return &OperationTimeoutError{
+					OperationID: operationID,
+					LastState:   lastState,
+					Err:         ctx.Err(),
+				}
+			}
+
+			if !isTransientPollError(err) || transientFailures >= config.MaxTransientFailures {
+				return err
+			}
+
+			transientFailures++
+			log.Printf("[WARN] transient error polling operation %q (%d/%d): %v", operationID, transientFailures, config.MaxTransientFailures, err)
+		} else {
+			transientFailures = 0
+
+			if resp.Operation == nil {
+				return fmt.Errorf("operation %q not found in poll response", operationID)
+			}
+
+			state := resp.Operation.State
+			if state != lastState {
+				log.Printf("[INFO] operation %q state changed from %s to %s", operationID, stateOrUnknown(lastState), state)
+				lastState = state
+			}
+
+			if state == models.HashicorpCloudConsulamaAmaOperationStateDONE {
+				if resp.Operation.Error != nil {
+					return NewCRPErrorFromStatus(resp.Operation.Error)
+				}
+
+				return nil
+			}
+		}
+
+		interval = config.nextInterval(interval)
+		timer.Reset(config.withJitter(interval, rand.Float64()))
+	}
+}
+
+// stateOrUnknown returns the passed operation state, or UNKNOWN if it is empty.
+func stateOrUnknown(state models.HashicorpCloudConsulamaAmaOperationState) string {
+	if state == "" {
+		return "UNKNOWN"
+	}
+
+	return string(state)
+}
+
+// isTransientPollError determines if an error returned while polling an operation
+// may succeed when retried, i.e. it is a server side, throttling or network error.
+func isTransientPollError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var crpErr *CRPError
+	if errors.As(err, &crpErr) {
+		return isTransientStatusCode(crpErr.HTTPStatus)
+	}
+
+	var statusCode interface{}
+	var requestErr *azure.RequestError
+	var detailedErr autorest.DetailedError
+	if errors.As(err, &requestErr) {
+		statusCode = requestErr.StatusCode
+	} else if errors.As(err, &detailedErr) {
+		statusCode = detailedErr.StatusCode
+	}
+
+	if code, ok := statusCode.(int); ok && code != 0 {
+		return isTransientStatusCode(code)
+	}
+
+	// Errors without a status code occurred before a response was received.
+	return true
+}
+
+// isTransientStatusCode determines if an HTTP status code denotes a failure that may succeed when retried.
+func isTransientStatusCode(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+)
+
+// testPollConfig polls quickly so the tests do not have to wait.
+var testPollConfig = OperationPollConfig{
+	Interval:             time.Millisecond,
+	MaxInterval:          5 * time.Millisecond,
+	Multiplier:           2,
+	Jitter:               0.2,
+	MaxTransientFailures: 2,
+}
+
+// testOperationServer returns a server which responds to each request with the next of the
+// passed responses, a zero status code denoting a 200 response with the passed body. The last
+// response is repeated once all responses have been returned.
+func testOperationServer(t *testing.T, responses []testOperationResponse) (*httptest.Server, func() int) {
+	var mu sync.Mutex
+	polls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		response := responses[len(responses)-1]
+		if polls < len(responses) {
+			response = responses[polls]
+		}
+		polls++
+
+		w.Header().Set("Content-Type", "application/json")
+		if response.statusCode != 0 {
+			w.WriteHeader(response.statusCode)
+		}
+		_, _ = w.Write([]byte(response.body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return polls
+	}
+}
+
+// testOperationResponse is a response returned by the testOperationServer.
+type testOperationResponse struct {
+	statusCode int
+	body       string
+}
+
+// testPollClient returns a client that polls the passed server. Autorest itself retries
+// each 5xx response once, so every transient failure must be returned twice by the server.
+func testPollClient(baseURI string) CustomResourceProviderClient {
+	client := NewCustomResourceProviderClientWithBaseURI(baseURI, "subscription-id", "test")
+	client.OperationPollConfig = testPollConfig
+	client.RetryAttempts = 1
+	client.RetryDuration = time.Millisecond
+
+	return client
+}
+
+func Test_OperationPollConfig_nextInterval(t *testing.T) {
+	r := require.New(t)
+
+	config := OperationPollConfig{
+		Interval:    10 * time.Second,
+		MaxInterval: time.Minute,
+		Multiplier:  2,
+	}
+
+	r.Equal(20*time.Second, config.nextInterval(10*time.Second))
+	r.Equal(40*time.Second, config.nextInterval(20*time.Second))
+	r.Equal(time.Minute, config.nextInterval(40*time.Second))
+	r.Equal(10*time.Second, config.nextInterval(0))
+}
+
+func Test_OperationPollConfig_withJitter(t *testing.T) {
+	r := require.New(t)
+
+	config := OperationPollConfig{Jitter: 0.2}
+
+	r.Equal(8*time.Second, config.withJitter(10*time.Second, 0))
+	r.Equal(10*time.Second, config.withJitter(10*time.Second, 0.5))
+	r.True(config.withJitter(10*time.Second, 0.999) < 12*time.Second)
+}
+
+func Test_OperationPollConfig_Backoff(t *testing.T) {
+	r := require.New(t)
+
+	backoff := testPollConfig.Backoff()
+	for i := 0; i < 3; i++ {
+		r.NoError(backoff(context.Background()))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.Equal(context.Canceled, OperationPollConfig{Interval: time.Hour, MaxInterval: time.Hour}.Backoff()(ctx))
+}
+
+func Test_isTransientPollError(t *testing.T) {
+	tcs := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"network error": {
+			err:      errors.New("connection reset by peer"),
+			expected: true,
+		},
+		"context deadline": {
+			err:      context.DeadlineExceeded,
+			expected: false,
+		},
+		"throttled": {
+			err:      &CRPError{HTTPStatus: http.StatusTooManyRequests},
+			expected: true,
+		},
+		"service unavailable": {
+			err:      autorest.DetailedError{StatusCode: http.StatusServiceUnavailable},
+			expected: true,
+		},
+		"bad request": {
+			err:      &CRPError{HTTPStatus: http.StatusBadRequest, Code: 9},
+			expected: false,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(tc.expected, isTransientPollError(tc.err))
+		})
+	}
+}
+
+func TestPollOperation(t *testing.T) {
+	t.Run("done after transient failures", func(t *testing.T) {
+		r := require.New(t)
+
+		unavailable := testOperationResponse{statusCode: http.StatusServiceUnavailable, body: `{"code":14,"message":"unavailable"}`}
+		badGateway := testOperationResponse{statusCode: http.StatusBadGateway, body: `bad gateway`}
+
+		server, polls := testOperationServer(t, []testOperationResponse{
+			{body: `{"operation":{"id":"op","state":"PENDING"}}`},
+			unavailable, unavailable,
+			{body: `{"operation":{"id":"op","state":"RUNNING"}}`},
+			badGateway, badGateway,
+			unavailable, unavailable,
+			{body: `{"operation":{"id":"op","state":"DONE"}}`},
+		})
+
+		err := testPollClient(server.URL).PollOperation(context.Background(), "op", "/managed-rg", "app")
+		r.NoError(err)
+		r.Equal(9, polls())
+	})
+
+	t.Run("too many transient failures", func(t *testing.T) {
+		r := require.New(t)
+
+		server, polls := testOperationServer(t, []testOperationResponse{
+			{statusCode: http.StatusServiceUnavailable, body: `{"code":14,"message":"unavailable"}`},
+		})
+
+		err := testPollClient(server.URL).PollOperation(context.Background(), "op", "/managed-rg", "app")
+		r.Error(err)
+		r.Equal(http.StatusServiceUnavailable, err.(*CRPError).HTTPStatus)
+		r.Equal(6, polls())
+	})
+
+	t.Run("non transient failure", func(t *testing.T) {
+		r := require.New(t)
+
+		server, polls := testOperationServer(t, []testOperationResponse{
+			{statusCode: http.StatusBadRequest, body: `{"code":3,"message":"invalid operation id"}`},
+		})
+
+		err := testPollClient(server.URL).PollOperation(context.Background(), "op", "/managed-rg", "app")
+		r.EqualError(err, "INVALID_ARGUMENT (HTTP 400): invalid operation id")
+		r.Equal(1, polls())
+	})
+
+	t.Run("operation error", func(t *testing.T) {
+		r := require.New(t)
+
+		server, _ := testOperationServer(t, []testOperationResponse{
+			{body: `{"operation":{"id":"op","state":"DONE","error":{"code":9,"message":"cluster is not running"}}}`},
+		})
+
+		err := testPollClient(server.URL).PollOperation(context.Background(), "op", "/managed-rg", "app")
+		r.Equal(&CRPError{
+			HTTPStatus: http.StatusBadRequest,
+			Code:       9,
+			Message:    "cluster is not running",
+		}, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		r := require.New(t)
+
+		server, _ := testOperationServer(t, []testOperationResponse{
+			{body: `{"operation":{"id":"op","state":"RUNNING"}}`},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := testPollClient(server.URL).PollOperation(ctx, "op", "/managed-rg", "app")
+
+		var timeoutErr *OperationTimeoutError
+		r.True(errors.As(err, &timeoutErr))
+		r.Equal("op", timeoutErr.OperationID)
+		r.Equal(models.HashicorpCloudConsulamaAmaOperationStateRUNNING, timeoutErr.LastState)
+		r.True(errors.Is(err, context.DeadlineExceeded))
+		r.Contains(err.Error(), `timed out waiting for operation "op" to complete; last observed state: RUNNING`)
+	})
+}
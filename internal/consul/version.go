@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/go-version"
 	cs "github.com/hashicorp/hcp-sdk-go/clients/cloud-consul-service/preview/2021-02-04/models"
 	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
 	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
@@ -21,6 +23,18 @@ const hcpConsulAPIVersion = "2021-02-04"
 // platform_type is used by the request for versions to determine the subset of versions for HCS
 var platform_type = string(cs.HashicorpCloudConsul20210204PlatformTypeHCS)
 
+// The statuses of a Consul version on HCP.
+const (
+	// StatusRecommended denotes the version that new clusters should use.
+	StatusRecommended = "RECOMMENDED"
+
+	// StatusAvailable denotes a version that is generally available.
+	StatusAvailable = "AVAILABLE"
+
+	// StatusPreview denotes a version that is available for testing but not yet generally available.
+	StatusPreview = "PREVIEW"
+)
+
 // Version represents a Consul version and the status of that version in regards to availability on HCP.
 type Version struct {
 	// Version is the Consul product version.
@@ -30,6 +44,16 @@ type Version struct {
 	Status string `json:"status"`
 }
 
+// Semver parses the Consul product version as a semantic version.
+func (v Version) Semver() (*version.Version, error) {
+	return ParseVersion(v.Version)
+}
+
+// IsPreview determines if the version is a preview version.
+func (v Version) IsPreview() bool {
+	return v.Status == StatusPreview
+}
+
 // availableVersionsResponse is the body of the HCP Consul versions response.
 type availableVersionsResponse struct {
 	// Versions is a slice of available Consul versions and their statuses.
@@ -38,10 +62,13 @@ type availableVersionsResponse struct {
 
 // GetAvailableHCPConsulVersions retrieves a slice of supported/available Consul versions from the HCP Consul API.
 func GetAvailableHCPConsulVersions(ctx context.Context, hcpApiDomain string) ([]Version, error) {
-	apiDomain := strings.TrimPrefix(hcpApiDomain, "https://")
-	apiDomain = strings.TrimSuffix(hcpApiDomain, "/")
+	// The domain may include an explicit scheme, e.g. to point at a local HTTP server.
+	baseURL := strings.TrimSuffix(hcpApiDomain, "/")
+	if !strings.HasPrefix(baseURL, "https://") && !strings.HasPrefix(baseURL, "http://") {
+		baseURL = "https://" + baseURL
+	}
 
-	url := fmt.Sprintf("https://%s/consul/%s/versions?platform_type=%s", apiDomain, hcpConsulAPIVersion, platform_type)
+	url := fmt.Sprintf("%s/consul/%s/versions?platform_type=%s", baseURL, hcpConsulAPIVersion, platform_type)
 
 	client := http.Client{
 		Transport: &http.Transport{
@@ -73,7 +100,7 @@ func RecommendedVersion(versions []Version) string {
 	for _, v := range versions {
 		defaultVersion = v.Version
 
-		if v.Status == "RECOMMENDED" {
+		if v.Status == StatusRecommended {
 			return defaultVersion
 		}
 	}
@@ -83,19 +110,204 @@ func RecommendedVersion(versions []Version) string {
 
 // NormalizeVersion ensures the version starts with a 'v'
 func NormalizeVersion(version string) string {
-	return "v" + strings.TrimPrefix(version, "v")
+	return "v" + strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
+
+// ParseVersion parses a Consul version with or without a leading 'v', e.g. `1.9.5`, `v1.9.5`
+// or `v1.9.5+ent`.
+func ParseVersion(v string) (*version.Version, error) {
+	parsed, err := version.NewSemver(strings.TrimSpace(v))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Consul version %q: %v", v, err)
+	}
+
+	return parsed, nil
+}
+
+// CompareVersions compares two Consul versions. It returns -1, 0 or 1 if a is less than, equal
+// to or greater than b. Build metadata, e.g. `+ent`, is ignored.
+func CompareVersions(a, b string) (int, error) {
+	av, err := ParseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	bv, err := ParseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return av.Compare(bv), nil
+}
+
+// EqualVersions determines if two Consul versions are equal. Versions that cannot be parsed
+// are equal if they are the same after normalization.
+func EqualVersions(a, b string) bool {
+	c, err := CompareVersions(a, b)
+	if err != nil {
+		return NormalizeVersion(a) == NormalizeVersion(b)
+	}
+
+	return c == 0
+}
+
+// FindVersion returns the Consul version of the slice which is equal to the given version string.
+func FindVersion(version string, versions []Version) (Version, bool) {
+	for _, v := range versions {
+		if EqualVersions(version, v.Version) {
+			return v, true
+		}
+	}
+
+	return Version{}, false
 }
 
 // IsValidVersion determines that a given version string is contained within the slice of
 // available Consul versions.
 func IsValidVersion(version string, versions []Version) bool {
+	_, ok := FindVersion(version, versions)
+	return ok
+}
+
+// SortVersions sorts a slice of Consul versions in ascending order. Versions that cannot be parsed
+// are sorted after all other versions.
+func SortVersions(versions []Version) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, errI := versions[i].Semver()
+		vj, errJ := versions[j].Semver()
+
+		switch {
+		case errI != nil && errJ != nil:
+			return versions[i].Version < versions[j].Version
+		case errI != nil || errJ != nil:
+			return errJ != nil
+		default:
+			return vi.LessThan(vj)
+		}
+	})
+}
+
+// MatchingVersions returns the Consul versions that satisfy the constraint expression, e.g.
+// `~> 1.9` or `>= 1.8, < 1.10`, in ascending order. All versions match an empty constraint.
+// Pre-release versions, e.g. `v1.10.0-beta1`, only match constraints that reference a pre-release.
+func MatchingVersions(constraint string, versions []Version) ([]Version, error) {
+	var constraints version.Constraints
+	if strings.TrimSpace(constraint) != "" {
+		var err error
+		constraints, err = version.NewConstraint(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Consul version constraint %q: %v", constraint, err)
+		}
+	}
+
+	matching := make([]Version, 0, len(versions))
 	for _, v := range versions {
-		if version == v.Version {
-			return true
+		parsed, err := v.Semver()
+		if err != nil {
+			continue
+		}
+
+		if constraints == nil || constraints.Check(parsed) {
+			matching = append(matching, v)
+		}
+	}
+	SortVersions(matching)
+
+	return matching, nil
+}
+
+// LatestVersion returns the newest Consul version of the slice that is not a preview version.
+func LatestVersion(versions []Version) (Version, bool) {
+	var latest Version
+	var latestSemver *version.Version
+
+	for _, v := range versions {
+		if v.IsPreview() {
+			continue
+		}
+
+		parsed, err := v.Semver()
+		if err != nil {
+			continue
+		}
+
+		if latestSemver == nil || parsed.GreaterThan(latestSemver) {
+			latest, latestSemver = v, parsed
+		}
+	}
+
+	return latest, latestSemver != nil
+}
+
+// UpgradePath returns the Consul versions a cluster on the current version has to be upgraded to, in
+// order, to reach the target version. The upgrade versions are the versions the cluster can directly
+// be upgraded to and the available versions are all versions available on HCP. HCS upgrades a cluster
+// by at most one minor release at a time, so after the first upgrade each intermediate version is the
+// newest available version of at most the next minor release that is not a preview version.
+func UpgradePath(current, target string, upgradeVersions, availableVersions []Version) ([]Version, error) {
+	from, err := ParseVersion(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid current Consul version %q: %v", current, err)
+	}
+
+	targetSemver, err := ParseVersion(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Consul version %q: %v", target, err)
+	}
+
+	if !targetSemver.GreaterThan(from) {
+		return nil, fmt.Errorf("specified Consul version (%s) is not newer than the current version (%s)", target, current)
+	}
+
+	if v, ok := FindVersion(target, upgradeVersions); ok {
+		return []Version{v}, nil
+	}
+
+	targetVersion, ok := FindVersion(target, availableVersions)
+	if !ok {
+		return nil, fmt.Errorf("specified Consul version (%s) is unavailable; must be one of: %+v", target, availableVersions)
+	}
+
+	var path []Version
+	candidates := upgradeVersions
+	for {
+		var hop Version
+		var hopSemver *version.Version
+		for _, v := range candidates {
+			parsed, err := v.Semver()
+			if err != nil || v.IsPreview() || !parsed.GreaterThan(from) || !parsed.LessThan(targetSemver) {
+				continue
+			}
+
+			if path != nil && !isNextUpgrade(from, parsed) {
+				continue
+			}
+
+			if hopSemver == nil || parsed.GreaterThan(hopSemver) {
+				hop, hopSemver = v, parsed
+			}
+		}
+
+		if hopSemver == nil {
+			return nil, fmt.Errorf("no upgrade path from Consul version %s to %s is available", current, target)
+		}
+
+		path = append(path, hop)
+		from = hopSemver
+		candidates = availableVersions
+
+		if isNextUpgrade(from, targetSemver) {
+			return append(path, targetVersion), nil
 		}
 	}
+}
+
+// isNextUpgrade determines if a cluster on the from version can be upgraded to the to version,
+// i.e. the to version is newer and of the same or the next minor release.
+func isNextUpgrade(from, to *version.Version) bool {
+	fromSegments, toSegments := from.Segments(), to.Segments()
 
-	return false
+	return to.GreaterThan(from) && fromSegments[0] == toSegments[0] && toSegments[1] <= fromSegments[1]+1
 }
 
 // FromAMAVersions converts a slice of *HashicorpCloudConsulamaAmaVersion to a slice of
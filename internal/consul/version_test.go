@@ -4,6 +4,9 @@
 package consul
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -129,6 +132,26 @@ func Test_IsValidVersion(t *testing.T) {
 			},
 			expected: false,
 		},
+		"without a prefixed v": {
+			version: "1.9.0",
+			validVersions: []Version{
+				{
+					Version: "v1.9.0",
+					Status:  "RECOMMENDED",
+				},
+			},
+			expected: true,
+		},
+		"with build metadata": {
+			version: "v1.9.0+ent",
+			validVersions: []Version{
+				{
+					Version: "v1.9.0",
+					Status:  "RECOMMENDED",
+				},
+			},
+			expected: true,
+		},
 		"with no valid versions": {
 			version:       "v1.8.0",
 			validVersions: nil,
@@ -146,6 +169,40 @@ func Test_IsValidVersion(t *testing.T) {
 	}
 }
 
+func Test_GetAvailableHCPConsulVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/consul/"+hcpConsulAPIVersion+"/versions" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"versions":[{"version":"v1.9.4","status":"RECOMMENDED"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	tcs := map[string]struct {
+		hcpApiDomain string
+	}{
+		"explicit scheme": {
+			hcpApiDomain: server.URL,
+		},
+		"explicit scheme and trailing slash": {
+			hcpApiDomain: server.URL + "/",
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			versions, err := GetAvailableHCPConsulVersions(context.Background(), tc.hcpApiDomain)
+			r.NoError(err)
+			r.Equal([]Version{{Version: "v1.9.4", Status: "RECOMMENDED"}}, versions)
+		})
+	}
+}
+
 func Test_FromAMAVersions(t *testing.T) {
 	amaVersions := []models.HashicorpCloudConsulamaAmaVersion{
 		{
@@ -187,3 +244,239 @@ func Test_FromAMAVersions(t *testing.T) {
 	result := FromAMAVersions(input)
 	r.EqualValues(expectedVersions, result)
 }
+
+func Test_CompareVersions(t *testing.T) {
+	tcs := map[string]struct {
+		a           string
+		b           string
+		expected    int
+		expectedErr bool
+	}{
+		"less than": {
+			a:        "v1.9.5",
+			b:        "v1.10.0",
+			expected: -1,
+		},
+		"equal with and without a prefixed v": {
+			a:        "1.9.5",
+			b:        "v1.9.5",
+			expected: 0,
+		},
+		"equal with build metadata": {
+			a:        "v1.9.5+ent",
+			b:        "v1.9.5",
+			expected: 0,
+		},
+		"pre-release is less than release": {
+			a:        "v1.10.0-beta1",
+			b:        "v1.10.0",
+			expected: -1,
+		},
+		"greater than": {
+			a:        "v1.10.0",
+			b:        "v1.9.5",
+			expected: 1,
+		},
+		"invalid version": {
+			a:           "latest",
+			b:           "v1.9.5",
+			expectedErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result, err := CompareVersions(tc.a, tc.b)
+			if tc.expectedErr {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal(tc.expected, result)
+		})
+	}
+}
+
+func Test_SortVersions(t *testing.T) {
+	r := require.New(t)
+
+	versions := []Version{
+		{Version: "v1.10.0"},
+		{Version: "invalid"},
+		{Version: "v1.9.5"},
+		{Version: "1.8.10"},
+		{Version: "v1.10.0-beta1"},
+		{Version: "v1.9.10"},
+	}
+
+	SortVersions(versions)
+
+	r.Equal([]Version{
+		{Version: "1.8.10"},
+		{Version: "v1.9.5"},
+		{Version: "v1.9.10"},
+		{Version: "v1.10.0-beta1"},
+		{Version: "v1.10.0"},
+		{Version: "invalid"},
+	}, versions)
+}
+
+func Test_MatchingVersions(t *testing.T) {
+	versions := []Version{
+		{Version: "v1.10.0", Status: StatusPreview},
+		{Version: "v1.9.5", Status: StatusAvailable},
+		{Version: "v1.9.4", Status: StatusRecommended},
+		{Version: "v1.8.10", Status: StatusAvailable},
+		{Version: "v1.11.0-beta1", Status: StatusPreview},
+	}
+
+	tcs := map[string]struct {
+		constraint     string
+		expected       []string
+		expectedLatest string
+		expectedErr    string
+	}{
+		"no constraint": {
+			constraint:     "",
+			expected:       []string{"v1.8.10", "v1.9.4", "v1.9.5", "v1.10.0", "v1.11.0-beta1"},
+			expectedLatest: "v1.9.5",
+		},
+		"pinned minor version": {
+			constraint:     "~> 1.9.0",
+			expected:       []string{"v1.9.4", "v1.9.5"},
+			expectedLatest: "v1.9.5",
+		},
+		"pinned major version": {
+			constraint:     "~> 1.9",
+			expected:       []string{"v1.9.4", "v1.9.5", "v1.10.0"},
+			expectedLatest: "v1.9.5",
+		},
+		"range": {
+			constraint:     ">= 1.8, < 1.9.5",
+			expected:       []string{"v1.8.10", "v1.9.4"},
+			expectedLatest: "v1.9.4",
+		},
+		"pre-release": {
+			constraint:     ">= 1.11.0-beta1",
+			expected:       []string{"v1.11.0-beta1"},
+			expectedLatest: "",
+		},
+		"no match": {
+			constraint:     "> 2.0",
+			expected:       []string{},
+			expectedLatest: "",
+		},
+		"invalid constraint": {
+			constraint:  "=> 1.9",
+			expectedErr: `invalid Consul version constraint "=> 1.9": malformed constraint: => 1.9`,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result, err := MatchingVersions(tc.constraint, versions)
+			if tc.expectedErr != "" {
+				r.EqualError(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+
+			matching := make([]string, 0, len(result))
+			for _, v := range result {
+				matching = append(matching, v.Version)
+			}
+			r.Equal(tc.expected, matching)
+
+			latest, ok := LatestVersion(result)
+			r.Equal(tc.expectedLatest != "", ok)
+			r.Equal(tc.expectedLatest, latest.Version)
+		})
+	}
+}
+
+func Test_UpgradePath(t *testing.T) {
+	availableVersions := []Version{
+		{Version: "v1.7.9", Status: StatusAvailable},
+		{Version: "v1.8.10", Status: StatusAvailable},
+		{Version: "v1.9.4", Status: StatusRecommended},
+		{Version: "v1.9.5", Status: StatusAvailable},
+		{Version: "v1.10.0", Status: StatusAvailable},
+		{Version: "v1.10.1", Status: StatusPreview},
+		{Version: "v1.11.2", Status: StatusAvailable},
+		{Version: "v2.0.0", Status: StatusPreview},
+	}
+
+	tcs := map[string]struct {
+		current         string
+		target          string
+		upgradeVersions []Version
+		expected        []string
+		expectedErr     string
+	}{
+		"direct upgrade": {
+			current:         "v1.8.10",
+			target:          "1.9.5",
+			upgradeVersions: availableVersions[2:4],
+			expected:        []string{"v1.9.5"},
+		},
+		"one intermediate version": {
+			current:         "v1.8.10",
+			target:          "v1.10.0",
+			upgradeVersions: availableVersions[2:4],
+			expected:        []string{"v1.9.5", "v1.10.0"},
+		},
+		"several intermediate versions": {
+			current:         "v1.7.9",
+			target:          "v1.11.2",
+			upgradeVersions: availableVersions[1:2],
+			expected:        []string{"v1.8.10", "v1.9.5", "v1.10.0", "v1.11.2"},
+		},
+		"target not newer": {
+			current:     "v1.9.4",
+			target:      "v1.8.10",
+			expectedErr: "specified Consul version (v1.8.10) is not newer than the current version (v1.9.4)",
+		},
+		"target unavailable": {
+			current:         "v1.9.4",
+			target:          "v1.12.0",
+			upgradeVersions: availableVersions[3:5],
+			expectedErr:     "specified Consul version (v1.12.0) is unavailable",
+		},
+		"no upgrade path": {
+			current:         "v1.9.4",
+			target:          "v2.0.0",
+			upgradeVersions: availableVersions[3:5],
+			expectedErr:     "no upgrade path from Consul version v1.9.4 to v2.0.0 is available",
+		},
+		"invalid current version": {
+			current:     "latest",
+			target:      "v1.9.5",
+			expectedErr: `invalid current Consul version "latest"`,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result, err := UpgradePath(tc.current, tc.target, tc.upgradeVersions, availableVersions)
+			if tc.expectedErr != "" {
+				r.Error(err)
+				r.Contains(err.Error(), tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+
+			path := make([]string, 0, len(result))
+			for _, v := range result {
+				path = append(path, v.Version)
+			}
+			r.Equal(tc.expected, path)
+		})
+	}
+}
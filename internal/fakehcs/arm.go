@@ -0,0 +1,456 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakehcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+// application is the Azure Resource Manager representation of a Managed Application.
+type application struct {
+	ID         string                `json:"id"`
+	Name       string                `json:"name"`
+	Type       string                `json:"type"`
+	Location   string                `json:"location"`
+	Kind       string                `json:"kind,omitempty"`
+	Tags       map[string]string     `json:"tags,omitempty"`
+	Plan       *applicationPlan      `json:"plan,omitempty"`
+	Properties applicationProperties `json:"properties"`
+}
+
+// applicationPlan is the Marketplace plan of a Managed Application.
+type applicationPlan struct {
+	Name          string `json:"name,omitempty"`
+	Publisher     string `json:"publisher,omitempty"`
+	Product       string `json:"product,omitempty"`
+	PromotionCode string `json:"promotionCode,omitempty"`
+	Version       string `json:"version,omitempty"`
+}
+
+// applicationProperties are the properties of a Managed Application.
+type applicationProperties struct {
+	ManagedResourceGroupID string                          `json:"managedResourceGroupId"`
+	Parameters             map[string]applicationParameter `json:"parameters,omitempty"`
+	ProvisioningState      string                          `json:"provisioningState,omitempty"`
+}
+
+// applicationParameter is a parameter passed to the Managed Application deployment.
+type applicationParameter struct {
+	Value interface{} `json:"value"`
+}
+
+// parameter returns the value of the deployment parameter as a string, or an empty string if it is not set.
+func (a *application) parameter(name string) string {
+	p, ok := a.Properties.Parameters[name]
+	if !ok || p.Value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(p.Value)
+}
+
+// armOperation is an async Azure Resource Manager operation, e.g. the deployment of a Managed Application.
+type armOperation struct {
+	// id is the ID of the operation.
+	id string
+
+	// polls is the number of remaining polls for which the operation is in progress.
+	polls int
+
+	// complete is called once the operation is done. The operation fails if it returns an error.
+	complete func() error
+
+	// status is the terminal status of the operation, empty while it is in progress.
+	status string
+
+	// err is the error of a failed operation.
+	err error
+}
+
+// purchaseCancellation is the cancellation of the Marketplace purchase of a deleted Managed Application.
+type purchaseCancellation struct {
+	// requests is the remaining number of requests until the purchase is canceled.
+	requests int
+
+	// managedResourceGroupID is the ID of the Managed Resource Group, which is deleted once the
+	// purchase is canceled.
+	managedResourceGroupID string
+}
+
+// serveResourceManager routes an Azure Resource Manager request.
+func (s *Server) serveResourceManager(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) < 2 || !strings.EqualFold(segments[0], "subscriptions") {
+		writeError(w, http.StatusNotFound, "NotFound", "the path %q is not supported", r.URL.Path)
+		return
+	}
+	if !strings.EqualFold(segments[1], s.SubscriptionID) {
+		writeError(w, http.StatusNotFound, "SubscriptionNotFound", "the subscription %q could not be found", segments[1])
+		return
+	}
+
+	s.advancePurchaseCancellations()
+
+	rest := segments[2:]
+	switch {
+	case matches(rest, "providers", "Microsoft.Solutions", "applications"):
+		s.listApplications(w, r, "")
+	case matches(rest, "providers", "Microsoft.Solutions", "operationStatuses", "*"):
+		s.getARMOperation(w, r, rest[3])
+	case matches(rest, "resourceGroups", "*"):
+		s.serveResource(w, r, s.resourceGroupID(rest[1]), "Microsoft.Resources/resourceGroups")
+	case matches(rest, "resourceGroups", "*", "providers", "Microsoft.Solutions", "applications"):
+		s.listApplications(w, r, rest[1])
+	case matches(rest, "resourceGroups", "*", "providers", "Microsoft.Solutions", "applications", "*"):
+		s.serveApplication(w, r, rest[1], rest[5])
+	case matches(rest, "resourceGroups", "*", "providers", "Microsoft.Network", "virtualNetworks", "*"):
+		s.serveResource(w, r, s.resourceGroupID(rest[1])+"/providers/Microsoft.Network/virtualNetworks/"+rest[5], "Microsoft.Network/virtualNetworks")
+	case matches(rest, "resourceGroups", "*", "providers", "Microsoft.ContainerService", "managedClusters", "*"):
+		s.serveResource(w, r, s.resourceGroupID(rest[1])+"/providers/Microsoft.ContainerService/managedClusters/"+rest[5], "Microsoft.ContainerService/managedClusters")
+	case len(rest) > 6 && matches(rest[:6], "resourceGroups", "*", "providers", "Microsoft.CustomProviders", "resourceProviders", "public"):
+		s.serveCustomResourceProvider(w, r, s.resourceGroupID(rest[1]), rest[6:])
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", "the path %q is not supported", r.URL.Path)
+	}
+}
+
+// resourceGroupID returns the ID of the Resource Group with the passed name.
+func (s *Server) resourceGroupID(name string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", s.SubscriptionID, name)
+}
+
+// hasResourceGroup determines if the Resource Group with the passed name exists.
+func (s *Server) hasResourceGroup(name string) bool {
+	_, ok := s.resources[key(s.resourceGroupID(name))]
+	return ok
+}
+
+// putResource stores a plain Azure resource, which is provisioned immediately.
+func (s *Server) putResource(id, resourceType string, body map[string]interface{}) map[string]interface{} {
+	properties, _ := body["properties"].(map[string]interface{})
+	if properties == nil {
+		properties = make(map[string]interface{})
+	}
+	properties["provisioningState"] = "Succeeded"
+
+	body["id"] = id
+	body["name"] = id[strings.LastIndex(id, "/")+1:]
+	body["type"] = resourceType
+	body["properties"] = properties
+	s.resources[key(id)] = body
+
+	return body
+}
+
+// deleteResources removes the resource with the passed ID and all resources nested within it.
+func (s *Server) deleteResources(id string) {
+	prefix := key(id)
+	for k := range s.resources {
+		if k == prefix || strings.HasPrefix(k, prefix+"/") {
+			delete(s.resources, k)
+		}
+	}
+}
+
+// serveResource serves the CRUD operations of a plain Azure resource, e.g. a Resource Group or VNet.
+func (s *Server) serveResource(w http.ResponseWriter, r *http.Request, id, resourceType string) {
+	isResourceGroup := resourceType == "Microsoft.Resources/resourceGroups"
+	resourceGroupName := strings.Split(id, "/")[4]
+
+	notFoundCode := "ResourceNotFound"
+	if isResourceGroup || !s.hasResourceGroup(resourceGroupName) {
+		notFoundCode = "ResourceGroupNotFound"
+	}
+
+	resource, ok := s.resources[key(id)]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !ok {
+			writeError(w, http.StatusNotFound, notFoundCode, "the resource %q could not be found", id)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resource)
+	case http.MethodPut:
+		if !isResourceGroup && !s.hasResourceGroup(resourceGroupName) {
+			writeError(w, http.StatusNotFound, notFoundCode, "the resource group %q could not be found", resourceGroupName)
+			return
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequestContent", "the request content is invalid: %v", err)
+			return
+		}
+
+		statusCode := http.StatusCreated
+		if ok {
+			statusCode = http.StatusOK
+		}
+		writeJSON(w, statusCode, s.putResource(id, resourceType, body))
+	case http.MethodDelete:
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		s.deleteResources(id)
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method %s is not allowed", r.Method)
+	}
+}
+
+// listApplications lists the Managed Applications of the subscription, or of the Resource Group if its name is not empty.
+func (s *Server) listApplications(w http.ResponseWriter, r *http.Request, resourceGroupName string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method %s is not allowed", r.Method)
+		return
+	}
+
+	if resourceGroupName != "" && !s.hasResourceGroup(resourceGroupName) {
+		writeError(w, http.StatusNotFound, "ResourceGroupNotFound", "the resource group %q could not be found", resourceGroupName)
+		return
+	}
+
+	prefix := key(s.resourceGroupID(resourceGroupName) + "/")
+	apps := make([]*application, 0)
+	for k, app := range s.applications {
+		if resourceGroupName == "" || strings.HasPrefix(k, prefix) {
+			apps = append(apps, app)
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return key(apps[i].ID) < key(apps[j].ID)
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"value": apps,
+	})
+}
+
+// serveApplication serves the CRUD operations of a Managed Application.
+func (s *Server) serveApplication(w http.ResponseWriter, r *http.Request, resourceGroupName, name string) {
+	id := s.resourceGroupID(resourceGroupName) + "/providers/Microsoft.Solutions/applications/" + name
+	app, ok := s.applications[key(id)]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !ok {
+			writeError(w, http.StatusNotFound, "ResourceNotFound", "the resource %q could not be found", id)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, app)
+	case http.MethodPut:
+		s.putApplication(w, r, resourceGroupName, id, app)
+	case http.MethodPatch:
+		if !ok {
+			writeError(w, http.StatusNotFound, "ResourceNotFound", "the resource %q could not be found", id)
+			return
+		}
+
+		var patch struct {
+			Tags map[string]string `json:"tags"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequestContent", "the request content is invalid: %v", err)
+			return
+		}
+
+		app.Tags = patch.Tags
+		writeJSON(w, http.StatusOK, app)
+	case http.MethodDelete:
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		s.deleteApplication(w, app)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method %s is not allowed", r.Method)
+	}
+}
+
+// putApplication creates a Managed Application and starts its async deployment, or updates
+// the tags of an existing one.
+func (s *Server) putApplication(w http.ResponseWriter, r *http.Request, resourceGroupName, id string, existing *application) {
+	if !s.hasResourceGroup(resourceGroupName) {
+		writeError(w, http.StatusNotFound, "ResourceGroupNotFound", "the resource group %q could not be found", resourceGroupName)
+		return
+	}
+
+	var app application
+	if err := json.NewDecoder(r.Body).Decode(&app); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequestContent", "the request content is invalid: %v", err)
+		return
+	}
+
+	if existing != nil {
+		if existing.Properties.ProvisioningState != "Succeeded" {
+			writeError(w, http.StatusConflict, "ApplicationNotInTerminalState", "the application %q is in state %s", id, existing.Properties.ProvisioningState)
+			return
+		}
+
+		existing.Tags = app.Tags
+		writeJSON(w, http.StatusOK, existing)
+		return
+	}
+
+	if _, ok := s.purchaseCancellations[key(id)]; ok {
+		writeError(w, http.StatusConflict, "ResourcePurchaseCanceling", "the purchase of the previously deleted application %q is being canceled", id)
+		return
+	}
+
+	if app.Plan == nil || app.Plan.Name == "" || app.Plan.Product == "" {
+		writeError(w, http.StatusBadRequest, "InvalidApplicationPlan", "a Marketplace plan is required")
+		return
+	}
+
+	mrgID := app.Properties.ManagedResourceGroupID
+	if !matches(strings.Split(strings.TrimPrefix(mrgID, "/"), "/"), "subscriptions", s.SubscriptionID, "resourceGroups", "*") {
+		writeError(w, http.StatusBadRequest, "InvalidApplicationManagedResourceGroupId", "the managed resource group ID %q is invalid", mrgID)
+		return
+	}
+	if _, ok := s.resources[key(mrgID)]; ok {
+		writeError(w, http.StatusConflict, "ApplicationManagedResourceGroupAlreadyExists", "the managed resource group %q already exists", mrgID)
+		return
+	}
+
+	app.ID = id
+	app.Name = id[strings.LastIndex(id, "/")+1:]
+	app.Type = "Microsoft.Solutions/applications"
+	app.Properties.ProvisioningState = "Accepted"
+	s.applications[key(id)] = &app
+
+	op := s.startARMOperation(func() error {
+		if err := s.provisionCluster(&app, resourceGroupName); err != nil {
+			app.Properties.ProvisioningState = "Failed"
+			return err
+		}
+
+		app.Properties.ProvisioningState = "Succeeded"
+		return nil
+	})
+
+	s.writeAsyncHeaders(w, op)
+	writeJSON(w, http.StatusCreated, &app)
+}
+
+// deleteApplication starts the async deletion of a Managed Application and its Managed Resource Group.
+func (s *Server) deleteApplication(w http.ResponseWriter, app *application) {
+	app.Properties.ProvisioningState = "Deleting"
+	mrgKey := key(app.Properties.ManagedResourceGroupID)
+	if c, ok := s.clusters[mrgKey]; ok {
+		c.response.Properties.State = "DELETING"
+	}
+
+	op := s.startARMOperation(func() error {
+		mrg := s.resources[mrgKey]
+
+		delete(s.applications, key(app.ID))
+		delete(s.clusters, mrgKey)
+		s.deleteResources(app.Properties.ManagedResourceGroupID)
+
+		// The Managed Resource Group is only deleted once the Marketplace purchase is canceled
+		if s.PurchaseCancellationRequests > 0 && mrg != nil {
+			mrg["properties"] = map[string]interface{}{"provisioningState": "Deleting"}
+			s.resources[mrgKey] = mrg
+			s.purchaseCancellations[key(app.ID)] = &purchaseCancellation{
+				requests:               s.PurchaseCancellationRequests,
+				managedResourceGroupID: app.Properties.ManagedResourceGroupID,
+			}
+		}
+
+		for token, primary := range s.federationTokens {
+			if primary == mrgKey {
+				delete(s.federationTokens, token)
+			}
+		}
+
+		return nil
+	})
+
+	s.writeAsyncHeaders(w, op)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// advancePurchaseCancellations counts a request towards the cancellation of the Marketplace purchases
+// of deleted Managed Applications and deletes the Managed Resource Groups of the canceled ones.
+func (s *Server) advancePurchaseCancellations() {
+	for k, c := range s.purchaseCancellations {
+		c.requests--
+		if c.requests <= 0 {
+			s.deleteResources(c.managedResourceGroupID)
+			delete(s.purchaseCancellations, k)
+		}
+	}
+}
+
+// startARMOperation starts an async operation that is completed by the passed func.
+func (s *Server) startARMOperation(complete func() error) *armOperation {
+	id, _ := uuid.GenerateUUID()
+
+	op := &armOperation{
+		id:       id,
+		polls:    s.OperationPolls,
+		complete: complete,
+	}
+	s.armOperations[id] = op
+
+	return op
+}
+
+// writeAsyncHeaders sets the headers pointing the client at the status of an async operation.
+// The Retry-After header prevents the Azure SDK from waiting its default polling delay.
+func (s *Server) writeAsyncHeaders(w http.ResponseWriter, op *armOperation) {
+	w.Header().Set("Azure-AsyncOperation", fmt.Sprintf("%s/subscriptions/%s/providers/Microsoft.Solutions/operationStatuses/%s", s.URL, s.SubscriptionID, op.id))
+	w.Header().Set("Retry-After", "0")
+}
+
+// getARMOperation returns the status of an async operation, which is completed once it has been
+// polled the configured number of times.
+func (s *Server) getARMOperation(w http.ResponseWriter, r *http.Request, id string) {
+	op, ok := s.armOperations[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "OperationNotFound", "the operation %q could not be found", id)
+		return
+	}
+
+	if op.status == "" {
+		if op.polls > 0 {
+			op.polls--
+		} else {
+			op.err = op.complete()
+			op.status = "Succeeded"
+			if op.err != nil {
+				op.status = "Failed"
+			}
+		}
+	}
+
+	body := map[string]interface{}{
+		"id":     r.URL.Path,
+		"name":   op.id,
+		"status": op.status,
+	}
+	if op.status == "" {
+		body["status"] = "InProgress"
+	}
+	if op.err != nil {
+		body["error"] = map[string]interface{}{
+			"code":    "DeploymentFailed",
+			"message": op.err.Error(),
+		}
+	}
+
+	w.Header().Set("Retry-After", "0")
+	writeJSON(w, http.StatusOK, body)
+}
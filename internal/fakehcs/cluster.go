@@ -0,0 +1,265 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakehcs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+)
+
+// cluster is an HCS cluster deployed into the Managed Resource Group of a Managed Application.
+type cluster struct {
+	// response is the cluster as returned by the Custom Resource Provider.
+	response *models.HashicorpCloudConsulamaAmaClusterResponse
+
+	// resourceGroupName is the name of the Resource Group of the Managed Application.
+	resourceGroupName string
+
+	// managedAppName is the name of the Managed Application.
+	managedAppName string
+
+	// planName is the name of the Marketplace plan of the Managed Application.
+	planName string
+
+	// createdAt is the time the cluster was created.
+	createdAt time.Time
+
+	// gossipKey is the Consul gossip encryption key.
+	gossipKey string
+
+	// caPEM is the PEM encoded CA certificate of the cluster.
+	caPEM string
+
+	// rootToken is the current root ACL token, nil if none has been created.
+	rootToken *models.HashicorpCloudConsulamaAmaACLToken
+
+	// snapshots are the snapshots of the cluster in the order they were requested.
+	snapshots []*models.HashicorpCloudConsulamaAmaSnapshotProperties
+
+	// primary is the lower case Managed Resource Group ID of the federation primary if the
+	// cluster is a secondary, empty otherwise.
+	primary string
+}
+
+// clientConfig is the Consul client agent config of a cluster.
+type clientConfig struct {
+	Datacenter            string                 `json:"datacenter"`
+	Encrypt               string                 `json:"encrypt"`
+	EncryptVerifyIncoming bool                   `json:"encrypt_verify_incoming"`
+	EncryptVerifyOutgoing bool                   `json:"encrypt_verify_outgoing"`
+	RetryJoin             []string               `json:"retry_join"`
+	VerifyOutgoing        bool                   `json:"verify_outgoing"`
+	VerifyServerHostname  bool                   `json:"verify_server_hostname"`
+	CAFile                string                 `json:"ca_file"`
+	AutoEncrypt           map[string]bool        `json:"auto_encrypt"`
+	ACL                   map[string]interface{} `json:"acl"`
+	Ports                 map[string]int         `json:"ports"`
+}
+
+// copyResponse returns a deep copy of the cluster response.
+func (c *cluster) copyResponse() models.HashicorpCloudConsulamaAmaClusterResponse {
+	response := *c.response
+	properties := *c.response.Properties
+	response.Properties = &properties
+
+	return response
+}
+
+// clientConfig returns the JSON encoded Consul client agent config of the cluster.
+func (c *cluster) clientConfig() string {
+	properties := c.response.Properties
+
+	config, _ := json.Marshal(clientConfig{
+		Datacenter:            properties.ConsulDatacenter,
+		Encrypt:               c.gossipKey,
+		EncryptVerifyIncoming: true,
+		EncryptVerifyOutgoing: true,
+		RetryJoin:             []string{strings.TrimPrefix(properties.ConsulPrivateEndpointURL, "https://")},
+		VerifyOutgoing:        true,
+		VerifyServerHostname:  true,
+		CAFile:                "./ca.pem",
+		AutoEncrypt:           map[string]bool{"tls": true},
+		ACL:                   map[string]interface{}{"enabled": true, "default_policy": "deny"},
+		Ports:                 map[string]int{"grpc": 8502},
+	})
+
+	return string(config)
+}
+
+// provisionCluster deploys the resources of a Managed Application: the Managed Resource Group,
+// the VNet and the HCS cluster configured by the deployment parameters.
+func (s *Server) provisionCluster(app *application, resourceGroupName string) error {
+	mrgID := app.Properties.ManagedResourceGroupID
+	mrgName := mrgID[strings.LastIndex(mrgID, "/")+1:]
+
+	var primary string
+	federationToken := app.parameter("federationToken")
+	if federationToken != "" {
+		var ok bool
+		primary, ok = s.federationTokens[federationToken]
+		if !ok {
+			return fmt.Errorf("the federation token is invalid or its primary cluster no longer exists")
+		}
+	}
+
+	clusterName := app.parameter("clusterName")
+	if clusterName == "" {
+		clusterName = app.Name
+	}
+
+	datacenter := app.parameter("consulDataCenter")
+	if datacenter == "" {
+		datacenter = clusterName
+	}
+
+	mode := models.HashicorpCloudConsulamaAmaClusterModePRODUCTION
+	numServers := "3"
+	if strings.EqualFold(app.parameter("clusterMode"), "DEVELOPMENT") {
+		mode = models.HashicorpCloudConsulamaAmaClusterModeDEVELOPMENT
+		numServers = "1"
+	}
+
+	auditLoggingEnabled := models.HashicorpCloudConsulamaAmaBooleanFALSE
+	if app.parameter("auditLoggingEnabled") == "enabled" {
+		auditLoggingEnabled = models.HashicorpCloudConsulamaAmaBooleanTRUE
+	}
+
+	clusterID, err := uuid.GenerateUUID()
+	if err != nil {
+		return err
+	}
+
+	gossipKey, err := uuid.GenerateRandomBytes(32)
+	if err != nil {
+		return err
+	}
+
+	caPEM, err := generateCA(datacenter)
+	if err != nil {
+		return err
+	}
+
+	externalEndpoint := app.parameter("externalEndpoint")
+	var externalEndpointURL string
+	if externalEndpoint == "enabled" {
+		externalEndpointURL = fmt.Sprintf("https://%s.consul.az.hashicorp.cloud", clusterID)
+	}
+
+	s.putResource(mrgID, "Microsoft.Resources/resourceGroups", map[string]interface{}{
+		"location":  app.Location,
+		"managedBy": app.ID,
+	})
+
+	vNetName := clusterName + "-vnet"
+	s.putResource(mrgID+"/providers/Microsoft.Network/virtualNetworks/"+vNetName, "Microsoft.Network/virtualNetworks", map[string]interface{}{
+		"location": app.Location,
+		"properties": map[string]interface{}{
+			"addressSpace": map[string]interface{}{
+				"addressPrefixes": []string{app.parameter("consulVnetCidr")},
+			},
+		},
+	})
+
+	var planName string
+	if app.Plan != nil {
+		planName = app.Plan.Name
+	}
+
+	c := &cluster{
+		response: &models.HashicorpCloudConsulamaAmaClusterResponse{
+			ID:   mrgID + "/providers/Microsoft.CustomProviders/resourceProviders/public/consulClusters/" + clusterName,
+			Name: clusterName,
+			Type: "Microsoft.CustomProviders/resourceProviders/public/consulClusters",
+			Properties: &models.HashicorpCloudConsulamaAmaClusterProperties{
+				AuditLogStorageContainerURL: app.parameter("auditLogStorageContainerURL"),
+				AuditLoggingEnabled:         auditLoggingEnabled,
+				BlobContainerName:           "consul-snapshots",
+				ConsulAutomaticUpgrades:     "enabled",
+				ConsulCaFile:                base64.StdEncoding.EncodeToString([]byte(caPEM)),
+				ConsulClusterID:             clusterID,
+				ConsulClusterMode:           mode,
+				ConsulConnect:               "enabled",
+				ConsulCurrentVersion:        app.parameter("initialConsulVersion"),
+				ConsulDatacenter:            datacenter,
+				ConsulExternalEndpoint:      externalEndpoint,
+				ConsulExternalEndpointURL:   externalEndpointURL,
+				ConsulInitialVersion:        app.parameter("initialConsulVersion"),
+				ConsulNumServers:            numServers,
+				ConsulPrivateEndpointURL:    fmt.Sprintf("https://%s.private.consul.az.hashicorp.cloud", clusterID),
+				ConsulSnapshotInterval:      "24h",
+				ConsulSnapshotRetention:     "30d",
+				ConsulVnetCidr:              app.parameter("consulVnetCidr"),
+				Email:                       app.parameter("email"),
+				FederationToken:             federationToken,
+				Location:                    app.Location,
+				ManagedAppID:                app.ID,
+				ManagedIdentity:             mrgID + "/providers/Microsoft.ManagedIdentity/userAssignedIdentities/" + clusterName + "-identity",
+				SourceChannel:               app.parameter("sourceChannel"),
+				State:                       models.HashicorpCloudConsulamaAmaClusterStateRUNNING,
+				StorageAccountName:          "hcs" + strings.ReplaceAll(clusterID, "-", "")[:16],
+				StorageAccountResourceGroup: mrgName,
+				VnetName:                    vNetName,
+			},
+		},
+		resourceGroupName: resourceGroupName,
+		managedAppName:    app.Name,
+		planName:          planName,
+		createdAt:         time.Now().UTC(),
+		gossipKey:         base64.StdEncoding.EncodeToString(gossipKey),
+		caPEM:             caPEM,
+		primary:           primary,
+	}
+	c.response.Properties.ConsulConfigFile = base64.StdEncoding.EncodeToString([]byte(c.clientConfig()))
+	s.clusters[key(mrgID)] = c
+
+	return nil
+}
+
+// generateCA generates a self-signed PEM encoded CA certificate for a Consul datacenter.
+func generateCA(datacenter string) (string, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:   fmt.Sprintf("Consul Agent CA %s", datacenter),
+			Organization: []string{"HashiCorp Inc."},
+		},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.AddDate(5, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
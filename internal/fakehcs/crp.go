@@ -0,0 +1,742 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakehcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-openapi/strfmt"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/go-version"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+)
+
+// The gRPC status codes returned by the fake Custom Resource Provider.
+const (
+	codeInvalidArgument    = 3
+	codeNotFound           = 5
+	codeAlreadyExists      = 6
+	codeFailedPrecondition = 9
+	codeUnimplemented      = 12
+	codeInternal           = 13
+)
+
+// codeHTTPStatus maps the returned gRPC status codes to the HTTP status used by the gRPC gateway.
+var codeHTTPStatus = map[int32]int{
+	codeInvalidArgument:    http.StatusBadRequest,
+	codeNotFound:           http.StatusNotFound,
+	codeAlreadyExists:      http.StatusConflict,
+	codeFailedPrecondition: http.StatusBadRequest,
+	codeUnimplemented:      http.StatusNotImplemented,
+	codeInternal:           http.StatusInternalServerError,
+}
+
+// hourlyPrices are the hourly prices billed per cluster mode.
+var hourlyPrices = map[models.HashicorpCloudConsulamaAmaClusterMode]float64{
+	models.HashicorpCloudConsulamaAmaClusterModeDEVELOPMENT: 0.1,
+	models.HashicorpCloudConsulamaAmaClusterModePRODUCTION:  2.9,
+}
+
+// federationTokenSigningKey is the key the fake federation tokens are signed with.
+var federationTokenSigningKey = []byte("fakehcs")
+
+// operation is an async Custom Resource Provider operation.
+type operation struct {
+	// operation is the operation as returned by the Custom Resource Provider.
+	operation *models.HashicorpCloudConsulamaAmaOperation
+
+	// polls is the number of remaining polls for which the operation is in progress.
+	polls int
+
+	// complete is called once the operation is done. The operation fails if it returns a status.
+	complete func() *models.GoogleRPCStatus
+}
+
+// serveCustomResourceProvider routes a Custom Resource Provider request for the Managed Resource Group.
+func (s *Server) serveCustomResourceProvider(w http.ResponseWriter, r *http.Request, managedResourceGroupID string, action []string) {
+	if _, ok := s.resources[key(managedResourceGroupID)]; !ok {
+		writeError(w, http.StatusNotFound, "ResourceGroupNotFound", "the resource group %q could not be found", managedResourceGroupID)
+		return
+	}
+
+	c := s.clusters[key(managedResourceGroupID)]
+
+	if strings.EqualFold(action[0], "consulClusters") {
+		switch {
+		case len(action) == 1 && r.Method == http.MethodGet:
+			s.listClusters(w, c)
+		case len(action) == 2 && r.Method == http.MethodGet:
+			s.getCluster(w, c, action[1])
+		case len(action) == 2 && r.Method == http.MethodPut:
+			s.createCluster(w, r, managedResourceGroupID, c, action[1])
+		case len(action) == 2 && r.Method == http.MethodDelete:
+			s.deleteCluster(w, managedResourceGroupID, c, action[1])
+		default:
+			writeRPCError(w, codeUnimplemented, "method %s is not supported", r.Method)
+		}
+		return
+	}
+
+	if len(action) != 1 || r.Method != http.MethodPost {
+		writeRPCError(w, codeUnimplemented, "action %q is not supported", strings.Join(action, "/"))
+		return
+	}
+
+	if c == nil {
+		writeRPCError(w, codeNotFound, "no cluster found in resource group %q", managedResourceGroupID)
+		return
+	}
+
+	switch strings.ToLower(action[0]) {
+	case "billinginfo":
+		s.getBillingInfo(w, r, c)
+	case "billingreport":
+		s.getBillingReport(w, r, c)
+	case "config":
+		s.getConfig(w, r, c)
+	case "createfederationtoken":
+		s.createFederationToken(w, r, managedResourceGroupID, c)
+	case "createsnapshot":
+		s.createSnapshot(w, r, c)
+	case "createtoken":
+		s.createToken(w, r, c)
+	case "deletesnapshot":
+		s.deleteSnapshot(w, r, c)
+	case "getfederation":
+		s.getFederation(w, r, managedResourceGroupID, c)
+	case "getsnapshot":
+		s.getSnapshot(w, r, c)
+	case "listconsulupgradeversions":
+		s.listUpgradeVersions(w, r, c)
+	case "listsnapshots":
+		s.listSnapshots(w, r, c)
+	case "operation":
+		s.getOperation(w, r)
+	case "renamesnapshot":
+		s.renameSnapshot(w, r, c)
+	case "restoresnapshot":
+		s.restoreSnapshot(w, r, c)
+	case "update":
+		s.updateCluster(w, r, c)
+	default:
+		writeRPCError(w, codeUnimplemented, "action %q is not supported", action[0])
+	}
+}
+
+// listClusters lists the cluster of the Managed Resource Group.
+func (s *Server) listClusters(w http.ResponseWriter, c *cluster) {
+	clusters := make([]*models.HashicorpCloudConsulamaAmaClusterResponse, 0, 1)
+	if c != nil {
+		response := c.copyResponse()
+		clusters = append(clusters, &response)
+	}
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaListClustersResponse{Value: clusters})
+}
+
+// getCluster returns the cluster of the Managed Resource Group if it has the passed name.
+func (s *Server) getCluster(w http.ResponseWriter, c *cluster, name string) {
+	if c == nil || !strings.EqualFold(c.response.Name, name) {
+		writeRPCError(w, codeNotFound, "cluster %q not found", name)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.copyResponse())
+}
+
+// createCluster creates the cluster resource of the Managed Resource Group from the passed properties.
+func (s *Server) createCluster(w http.ResponseWriter, r *http.Request, managedResourceGroupID string, c *cluster, name string) {
+	var req models.HashicorpCloudConsulamaAmaCreateClusterRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	if c != nil {
+		writeRPCError(w, codeAlreadyExists, "cluster %q already exists in resource group %q", c.response.Name, managedResourceGroupID)
+		return
+	}
+
+	properties := req.Properties
+	if properties == nil {
+		properties = &models.HashicorpCloudConsulamaAmaClusterProperties{}
+	}
+	properties.State = models.HashicorpCloudConsulamaAmaClusterStateRUNNING
+
+	c = &cluster{
+		response: &models.HashicorpCloudConsulamaAmaClusterResponse{
+			ID:         managedResourceGroupID + "/providers/Microsoft.CustomProviders/resourceProviders/public/consulClusters/" + name,
+			Name:       name,
+			Type:       "Microsoft.CustomProviders/resourceProviders/public/consulClusters",
+			Properties: properties,
+		},
+		resourceGroupName: req.ResourceGroup,
+		createdAt:         time.Now().UTC(),
+	}
+	s.clusters[key(managedResourceGroupID)] = c
+
+	writeJSON(w, http.StatusOK, c.copyResponse())
+}
+
+// deleteCluster removes the cluster resource of the Managed Resource Group.
+func (s *Server) deleteCluster(w http.ResponseWriter, managedResourceGroupID string, c *cluster, name string) {
+	if c == nil || !strings.EqualFold(c.response.Name, name) {
+		writeRPCError(w, codeNotFound, "cluster %q not found", name)
+		return
+	}
+
+	delete(s.clusters, key(managedResourceGroupID))
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+// getBillingInfo returns the billing settings of the cluster and its usage of today and this month.
+func (s *Server) getBillingInfo(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaGetBillingInfoRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	hourly := hourlyPrices[c.response.Properties.ConsulClusterMode]
+	now := time.Now().UTC()
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaGetBillingInfoResponse{
+		CurrentSettings: &models.HashicorpCloudConsulamaAmaBillingSettings{
+			ActiveSince: c.createdAt.Format(time.RFC3339),
+			Plan:        c.planName,
+			Prices: &models.HashicorpCloudConsulamaAmaBillingSettingsPrices{
+				Hourly:     hourly,
+				Dimensions: []*models.HashicorpCloudConsulamaAmaBillingSettingsPricesPriceDimension{},
+			},
+		},
+		Usage: &models.HashicorpCloudConsulamaAmaGetBillingInfoResponseCurrentUsage{
+			TimePeriod: &models.HashicorpCloudConsulamaAmaGetBillingInfoResponseCurrentUsagePeriods{
+				ThisMonth: c.billedUsage(startOfMonth, now, hourly),
+				Today:     c.billedUsage(startOfDay, now, hourly),
+			},
+		},
+	})
+}
+
+// billedUsage returns the usage of the cluster billed between start and end.
+func (c *cluster) billedUsage(start, end time.Time, hourly float64) *models.HashicorpCloudConsulamaAmaBilledUsage {
+	if c.createdAt.After(start) {
+		start = c.createdAt
+	}
+	hours := end.Sub(start).Hours()
+
+	return &models.HashicorpCloudConsulamaAmaBilledUsage{
+		Start: start.Format(time.RFC3339),
+		End:   end.Format(time.RFC3339),
+		Cost:  hours * hourly,
+		Details: []*models.HashicorpCloudConsulamaAmaBilledUsagePlanUsage{
+			{Plan: c.planName, Hours: hours},
+		},
+	}
+}
+
+// getBillingReport returns an item for each hour of the month in which the cluster existed.
+func (s *Server) getBillingReport(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaGetBillingReportRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	month, err := time.Parse("2006/01", req.Month)
+	if err != nil {
+		writeRPCError(w, codeInvalidArgument, "month %q must be of the format YYYY/MM", req.Month)
+		return
+	}
+
+	hourly := hourlyPrices[c.response.Properties.ConsulClusterMode]
+	servers, _ := strconv.Atoi(c.response.Properties.ConsulNumServers)
+
+	end := month.AddDate(0, 1, 0)
+	if now := time.Now().UTC(); now.Before(end) {
+		end = now
+	}
+
+	items := make([]*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItem, 0)
+	for hour := c.createdAt.Truncate(time.Hour); hour.Before(end); hour = hour.Add(time.Hour) {
+		if hour.Before(month) {
+			continue
+		}
+
+		items = append(items, &models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItem{
+			Start: hour.Format(time.RFC3339),
+			End:   hour.Add(time.Hour).Format(time.RFC3339),
+			Plan:  c.planName,
+			Cost:  hourly,
+			Dimensions: []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItemDimension{
+				{
+					Name: "servers",
+					Tiers: []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItemDimensionTier{
+						{Label: "standard", Units: int32(servers)},
+					},
+				},
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaGetBillingReportResponse{Usage: items})
+}
+
+// getConfig returns the Consul client agent config and CA file of the cluster.
+func (s *Server) getConfig(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaGetConfigRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaGetConfigResponse{
+		CaFile:       c.caPEM,
+		ClientConfig: c.clientConfig(),
+	})
+}
+
+// createFederationToken issues a token which allows new clusters to join the federation of the cluster.
+func (s *Server) createFederationToken(w http.ResponseWriter, r *http.Request, managedResourceGroupID string, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaCreateFederationTokenRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	if c.primary != "" {
+		writeRPCError(w, codeFailedPrecondition, "federation tokens can only be created for the primary datacenter of a federation")
+		return
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iat": time.Now().Unix(),
+		"Primary": map[string]string{
+			"ClusterID":            c.response.Properties.ConsulClusterID,
+			"ManagedResourceGroup": managedResourceGroupID,
+		},
+	}).SignedString(federationTokenSigningKey)
+	if err != nil {
+		writeRPCError(w, codeFailedPrecondition, "unable to sign federation token: %v", err)
+		return
+	}
+
+	s.federationTokens[token] = key(managedResourceGroupID)
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaCreateFederationTokenResponse{FederationToken: token})
+}
+
+// getFederation returns the federation the cluster is part of.
+func (s *Server) getFederation(w http.ResponseWriter, r *http.Request, managedResourceGroupID string, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaGetFederationRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	primaryKey := c.primary
+	if primaryKey == "" {
+		primaryKey = key(managedResourceGroupID)
+	}
+
+	secondaries := make([]*models.HashicorpCloudConsulamaAmaFederatedClusterResponse, 0)
+	for _, k := range s.sortedClusterKeys() {
+		if s.clusters[k].primary == primaryKey {
+			secondaries = append(secondaries, s.federatedCluster(s.clusters[k]))
+		}
+	}
+
+	if len(secondaries) == 0 {
+		writeRPCError(w, codeNotFound, "cluster %q is not part of a federation", c.response.Name)
+		return
+	}
+
+	response := models.HashicorpCloudConsulamaAmaGetFederationResponse{
+		SecondaryDatacenters: secondaries,
+		State:                models.HashicorpCloudConsulamaAmaGetFederationResponseFederationStateRUNNING,
+	}
+	if primary, ok := s.clusters[primaryKey]; ok {
+		response.PrimaryDatacenter = s.federatedCluster(primary)
+	} else {
+		response.State = models.HashicorpCloudConsulamaAmaGetFederationResponseFederationStatePRIMARYDATACENTERMISSING
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// federatedCluster returns the representation of a cluster as a member of a federation.
+func (s *Server) federatedCluster(c *cluster) *models.HashicorpCloudConsulamaAmaFederatedClusterResponse {
+	return &models.HashicorpCloudConsulamaAmaFederatedClusterResponse{
+		ID:             c.response.Properties.ConsulClusterID,
+		Name:           c.managedAppName,
+		ResourceGroup:  c.resourceGroupName,
+		SubscriptionID: s.SubscriptionID,
+	}
+}
+
+// sortedClusterKeys returns the keys of all clusters in ascending order.
+func (s *Server) sortedClusterKeys() []string {
+	keys := make([]string, 0, len(s.clusters))
+	for k := range s.clusters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// createToken creates a new root ACL token, which invalidates the previous one.
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaCreateTokenRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	accessorID, _ := uuid.GenerateUUID()
+	secretID, _ := uuid.GenerateUUID()
+	c.rootToken = &models.HashicorpCloudConsulamaAmaACLToken{
+		AccessorID: accessorID,
+		SecretID:   secretID,
+	}
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaCreateTokenResponse{MasterToken: c.rootToken})
+}
+
+// snapshot returns the snapshot of the cluster with the passed ID, or nil if there is none.
+func (c *cluster) snapshot(id string) *models.HashicorpCloudConsulamaAmaSnapshotProperties {
+	for _, snapshot := range c.snapshots {
+		if snapshot.ID == id {
+			return snapshot
+		}
+	}
+
+	return nil
+}
+
+// addSnapshot adds a pending snapshot to the cluster and returns the operation which completes it.
+func (s *Server) addSnapshot(c *cluster, name, snapshotType string) (*models.HashicorpCloudConsulamaAmaSnapshotProperties, *operation) {
+	id, _ := uuid.GenerateUUID()
+	snapshot := &models.HashicorpCloudConsulamaAmaSnapshotProperties{
+		ID:             id,
+		Name:           name,
+		ProductVersion: c.response.Properties.ConsulCurrentVersion,
+		RequestedAt:    strfmt.DateTime(time.Now().UTC()),
+		Size:           "0",
+		State:          "PENDING",
+		Type:           snapshotType,
+	}
+	c.snapshots = append(c.snapshots, snapshot)
+
+	op := s.startOperation(func() *models.GoogleRPCStatus {
+		snapshot.FinishedAt = strfmt.DateTime(time.Now().UTC())
+		snapshot.Size = "16384"
+		snapshot.State = "COMPLETED"
+		return nil
+	})
+
+	return snapshot, op
+}
+
+// createSnapshot starts an async operation which takes a manual snapshot of the cluster.
+func (s *Server) createSnapshot(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaCreateSnapshotRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		writeRPCError(w, codeInvalidArgument, "snapshot name must not be empty")
+		return
+	}
+
+	snapshot, op := s.addSnapshot(c, req.Name, "MANUAL")
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaCreateSnapshotResponse{
+		Operation:  op.operation,
+		SnapshotID: snapshot.ID,
+	})
+}
+
+// deleteSnapshot starts an async operation which deletes a snapshot of the cluster.
+func (s *Server) deleteSnapshot(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaDeleteSnapshotRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	if c.snapshot(req.SnapshotID) == nil {
+		writeRPCError(w, codeNotFound, "snapshot %q not found", req.SnapshotID)
+		return
+	}
+
+	op := s.startOperation(func() *models.GoogleRPCStatus {
+		for i, snapshot := range c.snapshots {
+			if snapshot.ID == req.SnapshotID {
+				c.snapshots = append(c.snapshots[:i], c.snapshots[i+1:]...)
+				break
+			}
+		}
+		return nil
+	})
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaDeleteSnapshotResponse{Operation: op.operation})
+}
+
+// getSnapshot returns a snapshot of the cluster.
+func (s *Server) getSnapshot(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaGetSnapshotRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	snapshot := c.snapshot(req.SnapshotID)
+	if snapshot == nil {
+		writeRPCError(w, codeNotFound, "snapshot %q not found", req.SnapshotID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaGetSnapshotResponse{Snapshot: snapshot})
+}
+
+// listSnapshots lists the snapshots of the cluster.
+func (s *Server) listSnapshots(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaListSnapshotsRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	snapshots := append([]*models.HashicorpCloudConsulamaAmaSnapshotProperties{}, c.snapshots...)
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaListSnapshotsResponse{Snapshots: snapshots})
+}
+
+// renameSnapshot changes the name of a snapshot of the cluster.
+func (s *Server) renameSnapshot(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaRenameSnapshotRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	snapshot := c.snapshot(req.SnapshotID)
+	if snapshot == nil {
+		writeRPCError(w, codeNotFound, "snapshot %q not found", req.SnapshotID)
+		return
+	}
+	if req.Name == "" {
+		writeRPCError(w, codeInvalidArgument, "snapshot name must not be empty")
+		return
+	}
+
+	snapshot.Name = req.Name
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaRenameSnapshotResponse{Snapshot: snapshot})
+}
+
+// restoreSnapshot starts an async operation which restores a snapshot into the cluster,
+// optionally taking a snapshot of the cluster first.
+func (s *Server) restoreSnapshot(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaRestoreSnapshotRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	snapshot := c.snapshot(req.SnapshotID)
+	if snapshot == nil {
+		writeRPCError(w, codeNotFound, "snapshot %q not found", req.SnapshotID)
+		return
+	}
+	if snapshot.State != "COMPLETED" {
+		writeRPCError(w, codeFailedPrecondition, "snapshot %q is in state %s and cannot be restored", req.SnapshotID, snapshot.State)
+		return
+	}
+
+	var preRestore *operation
+	if req.TakeSnapshot == models.HashicorpCloudConsulamaAmaBooleanTRUE {
+		_, preRestore = s.addSnapshot(c, "pre-restore-"+snapshot.Name, "MANUAL")
+	}
+
+	op := s.startOperation(func() *models.GoogleRPCStatus {
+		if preRestore != nil {
+			preRestore.finish()
+		}
+
+		snapshot.RestoredAt = strfmt.DateTime(time.Now().UTC())
+		return nil
+	})
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaRestoreSnapshotResponse{Operation: op.operation})
+}
+
+// upgradeVersions returns the Consul versions newer than the passed version of the same or the
+// next minor release.
+func (s *Server) upgradeVersions(current string) []*models.HashicorpCloudConsulamaAmaVersion {
+	currentVersion, err := version.NewVersion(current)
+
+	versions := make([]*models.HashicorpCloudConsulamaAmaVersion, 0)
+	for _, v := range s.ConsulVersions {
+		candidate, candidateErr := version.NewVersion(v.Version)
+		if err == nil && (candidateErr != nil || !candidate.GreaterThan(currentVersion) ||
+			candidate.Segments()[0] != currentVersion.Segments()[0] || candidate.Segments()[1] > currentVersion.Segments()[1]+1) {
+			continue
+		}
+
+		versions = append(versions, &models.HashicorpCloudConsulamaAmaVersion{
+			Version: v.Version,
+			Status:  models.HashicorpCloudConsulamaAmaVersionStatus(strings.ToUpper(v.Status)),
+		})
+	}
+
+	return versions
+}
+
+// listUpgradeVersions lists the Consul versions the cluster can be upgraded to.
+func (s *Server) listUpgradeVersions(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaListConsulUpgradeVersionsRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaListConsulUpgradeVersionsResponse{
+		Versions: s.upgradeVersions(c.response.Properties.ConsulCurrentVersion),
+	})
+}
+
+// updateCluster starts an async operation which upgrades the Consul version and/or changes
+// the audit logging configuration of the cluster.
+func (s *Server) updateCluster(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var req models.HashicorpCloudConsulamaAmaUpdateClusterRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	properties := c.response.Properties
+	if properties.State != models.HashicorpCloudConsulamaAmaClusterStateRUNNING {
+		writeRPCError(w, codeFailedPrecondition, "cluster %q is in state %s and cannot be updated", c.response.Name, properties.State)
+		return
+	}
+
+	update := req.Update
+	if update == nil {
+		update = &models.HashicorpCloudConsulamaAmaClusterUpdate{}
+	}
+
+	if update.ConsulVersion != "" {
+		valid := false
+		for _, v := range s.upgradeVersions(properties.ConsulCurrentVersion) {
+			if v.Version == update.ConsulVersion {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			writeRPCError(w, codeInvalidArgument, "cluster %q cannot be upgraded from %s to %s", c.response.Name, properties.ConsulCurrentVersion, update.ConsulVersion)
+			return
+		}
+	}
+
+	if update.AuditLogging != nil && update.AuditLogging.Enabled == models.HashicorpCloudConsulamaAmaBooleanTRUE && update.AuditLogging.StorageContainerURL == "" {
+		writeRPCError(w, codeInvalidArgument, "a storage container URL is required to enable audit logging")
+		return
+	}
+
+	op := s.startOperation(func() *models.GoogleRPCStatus {
+		for _, v := range s.FailingUpgradeVersions {
+			if v == update.ConsulVersion {
+				return &models.GoogleRPCStatus{
+					Code:    codeInternal,
+					Message: fmt.Sprintf("upgrade of cluster %q to %s failed", c.response.Name, update.ConsulVersion),
+				}
+			}
+		}
+
+		if update.ConsulVersion != "" {
+			properties.ConsulCurrentVersion = update.ConsulVersion
+		}
+
+		if update.AuditLogging != nil {
+			properties.AuditLoggingEnabled = update.AuditLogging.Enabled
+			properties.AuditLogStorageContainerURL = update.AuditLogging.StorageContainerURL
+		}
+
+		return nil
+	})
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaUpdateClusterResponse{Operation: op.operation})
+}
+
+// startOperation starts an async operation that is completed by the passed func.
+func (s *Server) startOperation(complete func() *models.GoogleRPCStatus) *operation {
+	id, _ := uuid.GenerateUUID()
+
+	op := &operation{
+		operation: &models.HashicorpCloudConsulamaAmaOperation{
+			ID:    id,
+			State: models.HashicorpCloudConsulamaAmaOperationStatePENDING,
+		},
+		polls:    s.OperationPolls,
+		complete: complete,
+	}
+	s.operations[id] = op
+
+	return op
+}
+
+// finish completes the operation unless it is already done.
+func (op *operation) finish() {
+	if op.operation.State == models.HashicorpCloudConsulamaAmaOperationStateDONE {
+		return
+	}
+
+	op.operation.Error = op.complete()
+	op.operation.State = models.HashicorpCloudConsulamaAmaOperationStateDONE
+}
+
+// getOperation returns the state of an async operation. The operation is PENDING until it is
+// first polled, RUNNING for the configured number of polls and DONE afterwards.
+func (s *Server) getOperation(w http.ResponseWriter, r *http.Request) {
+	var req models.HashicorpCloudConsulamaAmaGetOperationRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	op, ok := s.operations[req.OperationID]
+	if !ok {
+		writeRPCError(w, codeNotFound, "operation %q not found", req.OperationID)
+		return
+	}
+
+	if op.polls > 0 {
+		op.polls--
+		op.operation.State = models.HashicorpCloudConsulamaAmaOperationStateRUNNING
+	} else {
+		op.finish()
+	}
+
+	writeJSON(w, http.StatusOK, models.HashicorpCloudConsulamaAmaGetOperationResponse{Operation: op.operation})
+}
+
+// decodeRPCRequest decodes the JSON body of a request. It writes an INVALID_ARGUMENT error and
+// returns false if the body cannot be decoded.
+func decodeRPCRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeRPCError(w, codeInvalidArgument, "invalid request body: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// writeRPCError writes an error in the format of the gRPC gateway.
+func writeRPCError(w http.ResponseWriter, code int32, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+
+	writeJSON(w, codeHTTPStatus[code], models.GrpcGatewayRuntimeError{
+		Code:    code,
+		Error:   message,
+		Message: message,
+		Details: []*models.GoogleProtobufAny{},
+	})
+}
@@ -0,0 +1,363 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package fakehcs implements an in-memory fake of the Azure Resource Manager, HCS Custom Resource
+// Provider, HCS meta and HCP Consul APIs used by the provider, so that the provider can be tested
+// without an Azure subscription.
+package fakehcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/authentication"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+	"github.com/hashicorp/terraform-provider-hcs/internal/consul"
+	"github.com/hashicorp/terraform-provider-hcs/internal/hcsmeta"
+)
+
+const (
+	// DefaultSubscriptionID is the ID of the fake Azure subscription.
+	DefaultSubscriptionID = "00000000-0000-0000-0000-000000000000"
+
+	// MarketPlaceProductName is the HCS product name the client options of the server are configured with.
+	MarketPlaceProductName = "hcs-production"
+
+	// metaPathPrefix is the path under which the HCS meta repository is served.
+	metaPathPrefix = "/cloud-hcs-meta"
+
+	// consulVersionsPath is the path of the HCP Consul versions endpoint.
+	consulVersionsPath = "/consul/2021-02-04/versions"
+)
+
+// Server is a fake of the APIs used by the provider. All state is kept in memory and
+// lost when the server is closed.
+//
+// The exported fields may be changed between requests to configure the server.
+type Server struct {
+	*httptest.Server
+
+	// SubscriptionID is the ID of the fake Azure subscription.
+	SubscriptionID string
+
+	// Regions are the supported regions served by the HCS meta repository.
+	Regions []hcsmeta.SupportedRegion
+
+	// PlanDefaults are the plan defaults served by the HCS meta repository.
+	PlanDefaults hcsmeta.PlanDefaults
+
+	// ConsulVersions are the Consul versions served by the HCP Consul API in ascending order.
+	// A cluster can be upgraded to the versions newer than its current version of the same or
+	// the next minor release.
+	ConsulVersions []consul.Version
+
+	// FailingUpgradeVersions are the Consul versions to which the upgrade operations of a
+	// cluster fail.
+	FailingUpgradeVersions []string
+
+	// OperationPolls is the number of times an async operation is reported to be in
+	// progress before it is done.
+	OperationPolls int
+
+	// PurchaseCancellationRequests is the number of Azure Resource Manager requests it takes to
+	// cancel the Marketplace purchase of a deleted Managed Application. Until then, its Managed
+	// Resource Group is reported to be deleting and creating a Managed Application with the same
+	// ID fails with ResourcePurchaseCanceling.
+	PurchaseCancellationRequests int
+
+	mu sync.Mutex
+
+	// resources are the plain Azure resources, e.g. Resource Groups and VNets, keyed by lower case ID.
+	resources map[string]map[string]interface{}
+
+	// applications are the Managed Applications keyed by lower case ID.
+	applications map[string]*application
+
+	// clusters are the HCS clusters keyed by the lower case ID of their Managed Resource Group.
+	clusters map[string]*cluster
+
+	// armOperations are the async Azure Resource Manager operations keyed by ID.
+	armOperations map[string]*armOperation
+
+	// operations are the async Custom Resource Provider operations keyed by ID.
+	operations map[string]*operation
+
+	// purchaseCancellations are the Marketplace purchases of deleted Managed Applications that are
+	// being canceled, keyed by the lower case ID of the Managed Application.
+	purchaseCancellations map[string]*purchaseCancellation
+
+	// federationTokens maps the issued federation tokens to the lower case Managed Resource Group
+	// ID of the primary cluster.
+	federationTokens map[string]string
+
+	// notifications are the received Marketplace resource notifications.
+	notifications []models.HashicorpCloudConsulamaAmaResourceNotificationRequest
+}
+
+// NewServer starts and returns a new Server. The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		SubscriptionID: DefaultSubscriptionID,
+		Regions: []hcsmeta.SupportedRegion{
+			{ShortName: "eastus", FriendlyName: "East US"},
+			{ShortName: "westus2", FriendlyName: "West US 2"},
+			{ShortName: "westeurope", FriendlyName: "West Europe"},
+		},
+		PlanDefaults: hcsmeta.PlanDefaults{
+			Name:                 "on-demand-v2",
+			Version:              "0.0.46",
+			ManagedAppApiVersion: "2019-07-01",
+		},
+		ConsulVersions: []consul.Version{
+			{Version: "v1.8.10", Status: "AVAILABLE"},
+			{Version: "v1.9.4", Status: "RECOMMENDED"},
+			{Version: "v1.9.5", Status: "AVAILABLE"},
+			{Version: "v1.10.0", Status: "PREVIEW"},
+		},
+		OperationPolls:        2,
+		resources:             make(map[string]map[string]interface{}),
+		applications:          make(map[string]*application),
+		clusters:              make(map[string]*cluster),
+		armOperations:         make(map[string]*armOperation),
+		operations:            make(map[string]*operation),
+		purchaseCancellations: make(map[string]*purchaseCancellation),
+		federationTokens:      make(map[string]string),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// ClientOptions returns the options to build a client which sends all requests to the server
+// and polls async operations without delay.
+func (s *Server) ClientOptions() clients.Options {
+	return clients.Options{
+		ProviderUserAgent: "fakehcs",
+		AzureAuthConfig: &authentication.Config{
+			SubscriptionID: s.SubscriptionID,
+			Environment:    "public",
+		},
+		Config: clients.Config{
+			HCPApiDomain:           s.URL,
+			MarketPlaceProductName: MarketPlaceProductName,
+			SourceChannel:          "fakehcs",
+			OperationPollConfig: clients.OperationPollConfig{
+				Interval:             time.Millisecond,
+				MaxInterval:          10 * time.Millisecond,
+				Multiplier:           2,
+				MaxTransientFailures: 3,
+			},
+			HCSMetaSource: s.URL + metaPathPrefix,
+		},
+		LocalResourceManagerEndpoint: s.URL,
+	}
+}
+
+// AddResourceGroup adds a Resource Group to the subscription and returns its ID.
+func (s *Server) AddResourceGroup(name, location string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.resourceGroupID(name)
+	s.putResource(id, "Microsoft.Resources/resourceGroups", map[string]interface{}{
+		"location": location,
+	})
+
+	return id
+}
+
+// AddManagedCluster adds an AKS cluster to an existing Resource Group and returns its ID.
+func (s *Server) AddManagedCluster(resourceGroupName, name, fqdn, privateFQDN string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.resourceGroupID(resourceGroupName) + "/providers/Microsoft.ContainerService/managedClusters/" + name
+	properties := map[string]interface{}{}
+	if fqdn != "" {
+		properties["fqdn"] = fqdn
+	}
+	if privateFQDN != "" {
+		properties["privateFQDN"] = privateFQDN
+	}
+	s.putResource(id, "Microsoft.ContainerService/managedClusters", map[string]interface{}{
+		"location":   "westus2",
+		"properties": properties,
+	})
+
+	return id
+}
+
+// HasApplication determines if a Managed Application with the passed ID exists.
+func (s *Server) HasApplication(managedAppID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.applications[key(managedAppID)]
+	return ok
+}
+
+// Cluster returns a copy of the cluster of the passed Managed Application.
+func (s *Server) Cluster(managedAppID string) (models.HashicorpCloudConsulamaAmaClusterResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.clusterOfApplication(managedAppID)
+	if c == nil {
+		return models.HashicorpCloudConsulamaAmaClusterResponse{}, false
+	}
+
+	return c.copyResponse(), true
+}
+
+// UpdateCluster changes the properties of the cluster of the passed Managed Application, e.g. to
+// simulate changes made outside of Terraform. It returns false if the cluster does not exist.
+func (s *Server) UpdateCluster(managedAppID string, update func(properties *models.HashicorpCloudConsulamaAmaClusterProperties)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.clusterOfApplication(managedAppID)
+	if c == nil {
+		return false
+	}
+
+	update(c.response.Properties)
+	return true
+}
+
+// RootTokenAccessorID returns the accessor ID of the current root token of the cluster of
+// the passed Managed Application.
+func (s *Server) RootTokenAccessorID(managedAppID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.clusterOfApplication(managedAppID)
+	if c == nil || c.rootToken == nil {
+		return ""
+	}
+
+	return c.rootToken.AccessorID
+}
+
+// Notifications returns the Marketplace resource notifications received by the server.
+func (s *Server) Notifications() []models.HashicorpCloudConsulamaAmaResourceNotificationRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.HashicorpCloudConsulamaAmaResourceNotificationRequest(nil), s.notifications...)
+}
+
+// clusterOfApplication returns the cluster of the passed Managed Application, or nil if there is none.
+func (s *Server) clusterOfApplication(managedAppID string) *cluster {
+	app, ok := s.applications[key(managedAppID)]
+	if !ok {
+		return nil
+	}
+
+	return s.clusters[key(app.Properties.ManagedResourceGroupID)]
+}
+
+// serveHTTP routes a request to the fake API it is addressed to.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Resource IDs are escaped in Custom Resource Provider paths, which results in a duplicate leading slash.
+	path := "/" + strings.Trim(r.URL.Path, "/")
+
+	switch {
+	case strings.HasPrefix(path, metaPathPrefix+"/"):
+		s.serveMeta(w, r, strings.TrimPrefix(path, metaPathPrefix))
+	case path == consulVersionsPath:
+		s.serveConsulVersions(w, r)
+	case path == "/notifications/resource":
+		s.serveNotification(w, r)
+	default:
+		s.serveResourceManager(w, r, strings.Split(strings.TrimPrefix(path, "/"), "/"))
+	}
+}
+
+// serveMeta serves the files of the HCS meta repository.
+func (s *Server) serveMeta(w http.ResponseWriter, r *http.Request, path string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method %s is not allowed", r.Method)
+		return
+	}
+
+	switch path {
+	case "/ama-plans/defaults.json":
+		writeJSON(w, http.StatusOK, s.PlanDefaults)
+	case "/regions/regions.json":
+		writeJSON(w, http.StatusOK, struct {
+			Regions []hcsmeta.SupportedRegion `json:"regions"`
+		}{s.Regions})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveConsulVersions serves the Consul versions available on HCP.
+func (s *Server) serveConsulVersions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method %s is not allowed", r.Method)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Versions []consul.Version `json:"versions"`
+	}{s.ConsulVersions})
+}
+
+// serveNotification records a Marketplace resource notification.
+func (s *Server) serveNotification(w http.ResponseWriter, r *http.Request) {
+	var notification models.HashicorpCloudConsulamaAmaResourceNotificationRequest
+	if !decodeRPCRequest(w, r, &notification) {
+		return
+	}
+
+	s.notifications = append(s.notifications, notification)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+// key normalizes a resource ID for use as a map key, since Azure resource IDs are case insensitive.
+func key(id string) string {
+	return strings.ToLower(id)
+}
+
+// matches determines if the path segments match the pattern, where '*' matches any segment.
+func matches(segments []string, pattern ...string) bool {
+	if len(segments) != len(pattern) {
+		return false
+	}
+
+	for i, p := range pattern {
+		if p != "*" && !strings.EqualFold(p, segments[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// writeJSON writes the value as the JSON body of the response.
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an Azure Resource Manager error response.
+func writeError(w http.ResponseWriter, statusCode int, code, format string, args ...interface{}) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": fmt.Sprintf(format, args...),
+		},
+	})
+}
@@ -7,11 +7,33 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
-// MetaURLPrefix is the URL prefix for the HCS meta repository.
-const MetaURLPrefix = "https://raw.githubusercontent.com/hashicorp/cloud-hcs-meta/master"
+const (
+	// DefaultSource is the URL prefix of the public HCS meta repository.
+	DefaultSource = "https://raw.githubusercontent.com/hashicorp/cloud-hcs-meta/master"
+
+	// DefaultCacheTTL is the amount of time HCS meta files are cached for.
+	DefaultCacheTTL = 15 * time.Minute
+
+	// planDefaultsPath is the path of the AMA plan defaults file in the HCS meta repository.
+	planDefaultsPath = "ama-plans/defaults.json"
+
+	// supportedRegionsPath is the path of the supported regions file in the HCS meta repository.
+	supportedRegionsPath = "regions/regions.json"
+)
+
+// requestTimeout is the amount of time that can elapse before a request to a remote
+// HCS meta source times out.
+var requestTimeout = 10 * time.Second
 
 // PlanDefaults represents the default values of the current HCS Meta AMA plan.
 type PlanDefaults struct {
@@ -40,56 +62,182 @@ type supportedRegionsResponse struct {
 	Regions []SupportedRegion
 }
 
-// GetPlanDefaults gets the current HCS plan defaults from the HCS Meta repository.
-func GetPlanDefaults(ctx context.Context) (PlanDefaults, error) {
-	var planDefaults PlanDefaults
+// FallbackError is returned along with the embedded snapshot of a file when the file
+// cannot be read from the HCS meta source.
+type FallbackError struct {
+	// Path is the path of the file in the HCS meta repository.
+	Path string
 
-	url := MetaURLPrefix + "/ama-plans/defaults.json"
-	client := http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-		},
+	// Source is the URL prefix or local directory the file could not be read from.
+	Source string
+
+	// Err is the error that occurred reading the file.
+	Err error
+}
+
+func (e *FallbackError) Error() string {
+	return fmt.Sprintf("unable to read %s from HCS meta source %q, using the embedded snapshot instead: %v", e.Path, e.Source, e.Err)
+}
+
+func (e *FallbackError) Unwrap() error {
+	return e.Err
+}
+
+// cacheEntry is a file read from the HCS meta source.
+type cacheEntry struct {
+	// body is the content of the file.
+	body []byte
+
+	// expiresAt is the time after which the file must be read again.
+	expiresAt time.Time
+}
+
+// Client reads files from an HCS meta source, which is either the URL prefix or the local
+// directory of a copy of the HCS meta repository. Files are cached for the configured TTL.
+// If a file cannot be read and is not cached, the last known good snapshot embedded in the
+// provider is used instead. A Client is safe for concurrent use.
+type Client struct {
+	// source is the URL prefix or local directory of the HCS meta repository.
+	source string
+
+	// ttl is the amount of time files are cached for.
+	ttl time.Duration
+
+	// httpClient is the client used to read files from a remote source.
+	httpClient *http.Client
+
+	// group deduplicates concurrent reads of the same file from the source.
+	group singleflight.Group
+
+	// mu guards cache. It is not held while reading from the source.
+	mu sync.Mutex
+
+	// cache are the files read from the source keyed by path.
+	cache map[string]cacheEntry
+}
+
+// NewClient returns a Client for the passed source. DefaultSource is used if source is empty
+// and DefaultCacheTTL if ttl is zero.
+func NewClient(source string, ttl time.Duration) *Client {
+	if source == "" {
+		source = DefaultSource
 	}
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return planDefaults, err
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
 	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return planDefaults, fmt.Errorf("unable to retrieve HCS plan defaults: %v", err)
+
+	return &Client{
+		source: strings.TrimSuffix(source, "/"),
+		ttl:    ttl,
+		httpClient: &http.Client{
+			Timeout: requestTimeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+			},
+		},
+		cache: make(map[string]cacheEntry),
 	}
+}
+
+// Source returns the URL prefix or local directory the client reads from.
+func (c *Client) Source() string {
+	return c.source
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&planDefaults); err != nil {
+// GetPlanDefaults gets the current HCS plan defaults from the HCS Meta repository.
+// The embedded plan defaults are returned along with a *FallbackError if they cannot be read.
+func (c *Client) GetPlanDefaults(ctx context.Context) (PlanDefaults, error) {
+	var planDefaults PlanDefaults
+
+	body, fallbackErr := c.read(ctx, planDefaultsPath)
+	if err := json.Unmarshal(body, &planDefaults); err != nil {
 		return planDefaults, fmt.Errorf("unable to deserialize HCS plan defaults: %v", err)
 	}
 
-	return planDefaults, nil
+	return planDefaults, fallbackErr
 }
 
 // GetSupportedRegions gets the currently supported Azure regions from the HCS Meta repository.
-func GetSupportedRegions(ctx context.Context) ([]SupportedRegion, error) {
-	url := MetaURLPrefix + "/regions/regions.json"
-	client := http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-		},
+// The embedded regions are returned along with a *FallbackError if they cannot be read.
+func (c *Client) GetSupportedRegions(ctx context.Context) ([]SupportedRegion, error) {
+	var supportedRegionsBody supportedRegionsResponse
+
+	body, fallbackErr := c.read(ctx, supportedRegionsPath)
+	if err := json.Unmarshal(body, &supportedRegionsBody); err != nil {
+		return nil, fmt.Errorf("unable to deserialize supported HCS regions JSON: %v", err)
+	}
+
+	return supportedRegionsBody.Regions, fallbackErr
+}
+
+// read returns the content of a file of the HCS meta repository. The file is read from the cache
+// if it has not expired, and from the source otherwise. If the source cannot be read, the expired
+// cache entry or the embedded snapshot of the file is returned along with a *FallbackError.
+// Concurrent reads of the same file share a single read of the source.
+func (c *Client) read(ctx context.Context, path string) ([]byte, error) {
+	c.mu.Lock()
+	entry, cached := c.cache[path]
+	c.mu.Unlock()
+
+	if cached && time.Now().Before(entry.expiresAt) {
+		return entry.body, nil
+	}
+
+	// The shared read must not fail for the other callers when the context of the first caller
+	// is canceled. The HTTP client's timeout still bounds it.
+	body, err, _ := c.group.Do(path, func() (interface{}, error) {
+		return c.refresh(context.WithoutCancel(ctx), path)
+	})
+
+	return body.([]byte), err
+}
+
+// refresh reads a file from the source and caches it. If the source cannot be read, the cached
+// entry or the embedded snapshot of the file is returned along with a *FallbackError.
+func (c *Client) refresh(ctx context.Context, path string) ([]byte, error) {
+	body, err := c.readSource(ctx, path)
+	if err == nil && !json.Valid(body) {
+		err = fmt.Errorf("invalid JSON")
 	}
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if err != nil {
-		return nil, err
+		fallbackErr := &FallbackError{Path: path, Source: c.source, Err: err}
+		if entry, cached := c.cache[path]; cached {
+			return entry.body, fallbackErr
+		}
+
+		return []byte(snapshot[path]), fallbackErr
+	}
+
+	c.cache[path] = cacheEntry{body: body, expiresAt: time.Now().Add(c.ttl)}
+	return body, nil
+}
+
+// readSource reads a file from the remote or local source.
+func (c *Client) readSource(ctx context.Context, path string) ([]byte, error) {
+	if !strings.HasPrefix(c.source, "https://") && !strings.HasPrefix(c.source, "http://") {
+		return ioutil.ReadFile(filepath.Join(strings.TrimPrefix(c.source, "file://"), filepath.FromSlash(path)))
 	}
-	resp, err := client.Do(req)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.source+"/"+path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("unable to retrieve supported HCS regions: %v", err)
+		return nil, err
 	}
 
-	var supportedRegionsBody supportedRegionsResponse
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-	if err := json.NewDecoder(resp.Body).Decode(&supportedRegionsBody); err != nil {
-		return nil, fmt.Errorf("unable to deserialize supported HCS regions JSON: %v", err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
 	}
 
-	return supportedRegionsBody.Regions, nil
+	return ioutil.ReadAll(resp.Body)
 }
 
 // RegionIsSupported determines that a given region is supported by HCS.
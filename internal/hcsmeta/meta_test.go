@@ -4,7 +4,17 @@
 package hcsmeta
 
 import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -52,3 +62,167 @@ func Test_RegionIsSupported(t *testing.T) {
 		})
 	}
 }
+
+func TestClient(t *testing.T) {
+	planDefaults := `{"name":"on-demand-v3","version":"0.0.50","ama_api_version":"2019-07-01"}`
+	regions := `{"regions":[{"short":"westus2","friendly":"West US 2"}]}`
+
+	t.Run("remote source", func(t *testing.T) {
+		r := require.New(t)
+
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&requests, 1)
+
+			switch req.URL.Path {
+			case "/meta/ama-plans/defaults.json":
+				_, _ = w.Write([]byte(planDefaults))
+			case "/meta/regions/regions.json":
+				_, _ = w.Write([]byte(regions))
+			default:
+				http.NotFound(w, req)
+			}
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL+"/meta/", time.Hour)
+
+		defaults, err := client.GetPlanDefaults(context.Background())
+		r.NoError(err)
+		r.Equal(PlanDefaults{Name: "on-demand-v3", Version: "0.0.50", ManagedAppApiVersion: "2019-07-01"}, defaults)
+
+		supportedRegions, err := client.GetSupportedRegions(context.Background())
+		r.NoError(err)
+		r.Equal([]SupportedRegion{{ShortName: "westus2", FriendlyName: "West US 2"}}, supportedRegions)
+
+		// Cached files are not read again
+		_, err = client.GetPlanDefaults(context.Background())
+		r.NoError(err)
+		r.Equal(int32(2), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("local directory", func(t *testing.T) {
+		r := require.New(t)
+
+		dir, err := ioutil.TempDir("", "hcsmeta")
+		r.NoError(err)
+		defer os.RemoveAll(dir)
+
+		r.NoError(os.MkdirAll(filepath.Join(dir, "ama-plans"), 0755))
+		r.NoError(ioutil.WriteFile(filepath.Join(dir, "ama-plans", "defaults.json"), []byte(planDefaults), 0644))
+
+		defaults, err := NewClient(dir, 0).GetPlanDefaults(context.Background())
+		r.NoError(err)
+		r.Equal("on-demand-v3", defaults.Name)
+	})
+
+	t.Run("fallback to embedded snapshot", func(t *testing.T) {
+		r := require.New(t)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		supportedRegions, err := NewClient(server.URL, 0).GetSupportedRegions(context.Background())
+
+		var fallbackErr *FallbackError
+		r.True(errors.As(err, &fallbackErr))
+		r.Equal("regions/regions.json", fallbackErr.Path)
+		r.EqualError(fallbackErr.Err, "unexpected HTTP status 503 Service Unavailable")
+		r.True(RegionIsSupported("westus2", supportedRegions))
+	})
+
+	t.Run("fallback to expired cache", func(t *testing.T) {
+		r := require.New(t)
+
+		var available int32 = 1
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if atomic.LoadInt32(&available) == 0 {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(planDefaults))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, time.Nanosecond)
+
+		_, err := client.GetPlanDefaults(context.Background())
+		r.NoError(err)
+
+		atomic.StoreInt32(&available, 0)
+		time.Sleep(time.Millisecond)
+
+		defaults, err := client.GetPlanDefaults(context.Background())
+		var fallbackErr *FallbackError
+		r.True(errors.As(err, &fallbackErr))
+		r.Equal("on-demand-v3", defaults.Name)
+	})
+
+	t.Run("concurrent reads", func(t *testing.T) {
+		r := require.New(t)
+
+		var regionRequests int32
+		requested := make(chan struct{})
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path == "/ama-plans/defaults.json" {
+				_, _ = w.Write([]byte(planDefaults))
+				return
+			}
+
+			if atomic.AddInt32(&regionRequests, 1) == 1 {
+				close(requested)
+			}
+			<-release
+			_, _ = w.Write([]byte(regions))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, time.Hour)
+		_, err := client.GetPlanDefaults(context.Background())
+		r.NoError(err)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 5)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.GetSupportedRegions(context.Background())
+				errs <- err
+			}()
+		}
+		<-requested
+
+		// Cached files are read while another file is read from the source
+		_, err = client.GetPlanDefaults(context.Background())
+		r.NoError(err)
+
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			r.NoError(err)
+		}
+		r.Equal(int32(1), atomic.LoadInt32(&regionRequests))
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		r := require.New(t)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("<html>blocked</html>"))
+		}))
+		defer server.Close()
+
+		defaults, err := NewClient(server.URL, 0).GetPlanDefaults(context.Background())
+
+		var fallbackErr *FallbackError
+		r.True(errors.As(err, &fallbackErr))
+		r.NotEmpty(defaults.Name)
+	})
+}
@@ -0,0 +1,31 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcsmeta
+
+// snapshot is the last known good content of the files of the HCS meta repository keyed by path.
+// It is used when the HCS meta source cannot be read, e.g. in networks without access to GitHub,
+// and should be updated whenever the HCS meta repository changes.
+var snapshot = map[string]string{
+	planDefaultsPath: `{
+  "name": "on-demand-v2",
+  "version": "0.0.46",
+  "ama_api_version": "2019-07-01"
+}`,
+	supportedRegionsPath: `{
+  "regions": [
+    {"short": "australiaeast", "friendly": "Australia East"},
+    {"short": "canadacentral", "friendly": "Canada Central"},
+    {"short": "centralus", "friendly": "Central US"},
+    {"short": "eastus", "friendly": "East US"},
+    {"short": "eastus2", "friendly": "East US 2"},
+    {"short": "francecentral", "friendly": "France Central"},
+    {"short": "japaneast", "friendly": "Japan East"},
+    {"short": "northeurope", "friendly": "North Europe"},
+    {"short": "southeastasia", "friendly": "Southeast Asia"},
+    {"short": "uksouth", "friendly": "UK South"},
+    {"short": "westeurope", "friendly": "West Europe"},
+    {"short": "westus2", "friendly": "West US 2"}
+  ]
+}`,
+}
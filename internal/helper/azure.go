@@ -4,9 +4,11 @@
 package helper
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
 )
 
 // IsAutoRestResponseCodeNotFound determines if an AutoRest response code was
@@ -32,6 +34,28 @@ func isAutoRestCode(resp autorest.Response, code int) bool {
 	return false
 }
 
+// IsAzureServiceErrorCode determines if an error returned by the Azure SDK carries the passed
+// Azure service error code, e.g. ResourcePurchaseCanceling.
+func IsAzureServiceErrorCode(err error, code string) bool {
+	// Depending on the request, the Azure SDK returns a request error by value or as a pointer
+	var requestErr *azure.RequestError
+	if errors.As(err, &requestErr) && requestErr.ServiceError != nil {
+		return requestErr.ServiceError.Code == code
+	}
+
+	var requestErrValue azure.RequestError
+	if errors.As(err, &requestErrValue) && requestErrValue.ServiceError != nil {
+		return requestErrValue.ServiceError.Code == code
+	}
+
+	var serviceErr *azure.ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr.Code == code
+	}
+
+	return false
+}
+
 // TagValueToString converts a tag interface{} to string.
 // Adapted from the azurerm provider.
 // https://github.com/terraform-providers/terraform-provider-azurerm/blob/b7299d0b8c6f3685db07586530a7f52216dd48e4/azurerm/internal/tags/validation.go#L31
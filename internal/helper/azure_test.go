@@ -4,9 +4,12 @@
 package helper
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/stretchr/testify/require"
 )
 
@@ -97,3 +100,53 @@ func Test_FlattenTags(t *testing.T) {
 		}
 	}
 }
+
+func Test_IsAzureServiceErrorCode(t *testing.T) {
+	tcs := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"detailed request error": {
+			err: autorest.DetailedError{
+				Original: &azure.RequestError{
+					ServiceError: &azure.ServiceError{Code: "ResourcePurchaseCanceling"},
+				},
+			},
+			expected: true,
+		},
+		"request error value": {
+			err: autorest.DetailedError{
+				Original: azure.RequestError{
+					ServiceError: &azure.ServiceError{Code: "ResourcePurchaseCanceling"},
+				},
+			},
+			expected: true,
+		},
+		"service error": {
+			err:      autorest.NewErrorWithError(&azure.ServiceError{Code: "ResourcePurchaseCanceling"}, "test", "test", nil, "failed"),
+			expected: true,
+		},
+		"other code": {
+			err: &azure.RequestError{
+				ServiceError: &azure.ServiceError{Code: "Conflict"},
+			},
+			expected: false,
+		},
+		"request error without service error": {
+			err:      &azure.RequestError{},
+			expected: false,
+		},
+		"other error": {
+			err:      errors.New("ResourcePurchaseCanceling"),
+			expected: false,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(tc.expected, IsAzureServiceErrorCode(tc.err, "ResourcePurchaseCanceling"))
+		})
+	}
+}
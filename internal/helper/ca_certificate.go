@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helper
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CACertificate holds the metadata of a PEM encoded CA certificate.
+type CACertificate struct {
+	// Subject is the distinguished name of the certificate subject.
+	Subject string
+	// SerialNumber is the colon separated hex encoded serial number of the certificate.
+	SerialNumber string
+	// SHA256Fingerprint is the colon separated hex encoded SHA-256 digest of the DER encoded certificate.
+	SHA256Fingerprint string
+	// NotBefore is the RFC 3339 timestamp from which the certificate is valid.
+	NotBefore string
+	// NotAfter is the RFC 3339 timestamp after which the certificate is no longer valid.
+	NotAfter string
+}
+
+// ParseCACertificate parses the first certificate of a PEM encoded CA chain.
+func ParseCACertificate(caPEM string) (*CACertificate, error) {
+	block, _ := pem.Decode([]byte(caPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("unable to decode CA certificate: no PEM encoded certificate found")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse CA certificate: %+v", err)
+	}
+
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	return &CACertificate{
+		Subject:           cert.Subject.String(),
+		SerialNumber:      colonHex(cert.SerialNumber.Bytes()),
+		SHA256Fingerprint: colonHex(fingerprint[:]),
+		NotBefore:         cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:          cert.NotAfter.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// colonHex formats b as colon separated hex bytes, e.g. 0a:1b:2c.
+func colonHex(b []byte) string {
+	parts := make([]string, len(b))
+	for i, v := range b {
+		parts[i] = fmt.Sprintf("%02x", v)
+	}
+
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helper
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseCACertificate(t *testing.T) {
+	r := require.New(t)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	r.NoError(err)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(0x0a1b2c),
+		Subject:               pkix.Name{CommonName: "Consul Agent CA", Organization: []string{"HashiCorp Inc."}},
+		NotBefore:             time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	r.NoError(err)
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	tcs := map[string]struct {
+		caPEM     string
+		expectErr bool
+	}{
+		"valid certificate": {
+			caPEM: caPEM,
+		},
+		"empty": {
+			caPEM:     "",
+			expectErr: true,
+		},
+		"private key": {
+			caPEM:     string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")})),
+			expectErr: true,
+		},
+		"invalid certificate": {
+			caPEM:     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("not a certificate")})),
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			cert, err := ParseCACertificate(tc.caPEM)
+			if tc.expectErr {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal("CN=Consul Agent CA,O=HashiCorp Inc.", cert.Subject)
+			r.Equal("0a:1b:2c", cert.SerialNumber)
+			r.Len(cert.SHA256Fingerprint, 32*3-1)
+			r.Equal("2021-01-01T00:00:00Z", cert.NotBefore)
+			r.Equal("2026-01-01T00:00:00Z", cert.NotAfter)
+		})
+	}
+}
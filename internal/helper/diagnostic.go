@@ -9,6 +9,8 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/hcsmeta"
 )
 
 // ToError will convert the passed in diag.Diagnostics d
@@ -34,3 +36,53 @@ func ToError(d diag.Diagnostics) *multierror.Error {
 
 	return err
 }
+
+// detailedError is implemented by errors that carry a short summary and a
+// longer description, such as the decoded Custom Resource Provider errors.
+type detailedError interface {
+	error
+	Summary() string
+	Detail() string
+}
+
+// ErrorDiagnostics returns an error diagnostic for err whose summary starts with the
+// message built from format and args. If err carries a detailed description, the
+// summary ends with the error summary and the description is used as the detail.
+func ErrorDiagnostics(err error, format string, args ...interface{}) diag.Diagnostics {
+	summary := fmt.Sprintf(format, args...)
+
+	var detailed detailedError
+	if errors.As(err, &detailed) {
+		return diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("%s: %s", summary, detailed.Summary()),
+				Detail:   detailed.Detail(),
+			},
+		}
+	}
+
+	return diag.Errorf("%s: %v", summary, err)
+}
+
+// MetaDiagnostics returns the diagnostics for an error returned by an hcsmeta.Client, prefixed
+// with the message built from format and args. Falling back to the embedded HCS meta snapshot
+// results in a warning, since the snapshot can still be used. It returns nil if err is nil.
+func MetaDiagnostics(err error, format string, args ...interface{}) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+
+	var fallbackErr *hcsmeta.FallbackError
+	if errors.As(err, &fallbackErr) {
+		return diag.Diagnostics{
+			{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf(format, args...),
+				Detail:   fallbackErr.Error(),
+			},
+		}
+	}
+
+	return diag.Errorf("%s: %v", fmt.Sprintf(format, args...), err)
+}
@@ -5,11 +5,14 @@ package helper
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/hcsmeta"
 )
 
 func Test_toError(t *testing.T) {
@@ -78,3 +81,105 @@ func Test_toError(t *testing.T) {
 		})
 	}
 }
+
+type testDetailedError struct{}
+
+func (testDetailedError) Error() string   { return "summary and detail" }
+func (testDetailedError) Summary() string { return "summary" }
+func (testDetailedError) Detail() string  { return "detail" }
+
+func Test_ErrorDiagnostics(t *testing.T) {
+	tcs := []struct {
+		name string
+		err  error
+		diag diag.Diagnostics
+	}{
+		{
+			name: "plain error",
+			err:  errors.New("there was an error"),
+			diag: []diag.Diagnostic{
+				{
+					Severity: diag.Error,
+					Summary:  "unable to fetch (ID \"1\"): there was an error",
+				},
+			},
+		},
+		{
+			name: "detailed error",
+			err:  testDetailedError{},
+			diag: []diag.Diagnostic{
+				{
+					Severity: diag.Error,
+					Summary:  "unable to fetch (ID \"1\"): summary",
+					Detail:   "detail",
+				},
+			},
+		},
+		{
+			name: "wrapped detailed error",
+			err:  fmt.Errorf("wrapped: %w", testDetailedError{}),
+			diag: []diag.Diagnostic{
+				{
+					Severity: diag.Error,
+					Summary:  "unable to fetch (ID \"1\"): summary",
+					Detail:   "detail",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(tc.diag, ErrorDiagnostics(tc.err, "unable to fetch (ID %q)", "1"))
+		})
+	}
+}
+
+func TestMetaDiagnostics(t *testing.T) {
+	fallbackErr := &hcsmeta.FallbackError{
+		Path:   "regions/regions.json",
+		Source: "https://example.com",
+		Err:    errors.New("unexpected HTTP status 404 Not Found"),
+	}
+
+	tcs := []struct {
+		name string
+		err  error
+		diag diag.Diagnostics
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name: "fallback",
+			err:  fallbackErr,
+			diag: []diag.Diagnostic{
+				{
+					Severity: diag.Warning,
+					Summary:  "unable to retrieve regions",
+					Detail:   "unable to read regions/regions.json from HCS meta source \"https://example.com\", using the embedded snapshot instead: unexpected HTTP status 404 Not Found",
+				},
+			},
+		},
+		{
+			name: "error",
+			err:  errors.New("unable to deserialize"),
+			diag: []diag.Diagnostic{
+				{
+					Severity: diag.Error,
+					Summary:  "unable to retrieve regions: unable to deserialize",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(tc.diag, MetaDiagnostics(tc.err, "unable to retrieve %s", "regions"))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// keybasePrefix is the prefix of a Keybase username reference to a PGP key.
+const keybasePrefix = "keybase:"
+
+// KeybaseUsername returns the username of a Keybase reference to a PGP key (keybase:username)
+// and whether the passed PGP key is a Keybase reference.
+func KeybaseUsername(pgpKey string) (string, bool) {
+	pgpKey = strings.TrimSpace(pgpKey)
+	if !strings.HasPrefix(pgpKey, keybasePrefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(pgpKey, keybasePrefix), true
+}
+
+// ResolvePGPKey resolves a Keybase reference (keybase:username) to the exported public key of the
+// user in keybaseKeys, which maps Keybase usernames to their keys. The keys are not fetched from
+// keybase.io, since that would make plans depend on it. Other PGP keys are returned unchanged.
+func ResolvePGPKey(pgpKey string, keybaseKeys map[string]string) (string, error) {
+	username, ok := KeybaseUsername(pgpKey)
+	if !ok {
+		return pgpKey, nil
+	}
+
+	if username == "" {
+		return "", fmt.Errorf("keybase reference %q has no username", pgpKey)
+	}
+
+	key, ok := keybaseKeys[username]
+	if !ok {
+		return "", fmt.Errorf("no public key for keybase user %q; export it with `keybase pgp export -q %s` and pass it in keybase_public_keys",
+			username,
+			username,
+		)
+	}
+
+	return key, nil
+}
+
+// ParsePGPKey parses a PGP public key, which is either base64 encoded or ASCII armored.
+// Keybase references must be resolved with ResolvePGPKey first.
+func ParsePGPKey(pgpKey string) (*openpgp.Entity, error) {
+	pgpKey = strings.TrimSpace(pgpKey)
+
+	if username, ok := KeybaseUsername(pgpKey); ok {
+		return nil, fmt.Errorf("keybase reference to user %q must be resolved to the exported public key", username)
+	}
+
+	if strings.HasPrefix(pgpKey, "-----BEGIN") {
+		block, err := armor.Decode(strings.NewReader(pgpKey))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode ASCII armored PGP key: %v", err)
+		}
+
+		entity, err := openpgp.ReadEntity(packet.NewReader(block.Body))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse PGP key: %v", err)
+		}
+
+		return entity, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(pgpKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode base64 encoded PGP key: %v", err)
+	}
+
+	entity, err := openpgp.ReadEntity(packet.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse PGP key: %v", err)
+	}
+
+	return entity, nil
+}
+
+// EncryptWithPGPKey encrypts a value with the passed PGP public key. The base64 encoded
+// encrypted value and the hex encoded fingerprint of the key are returned. The value can be
+// decrypted with `base64 --decode | gpg --decrypt`.
+func EncryptWithPGPKey(pgpKey, value string) (encrypted string, fingerprint string, err error) {
+	entity, err := ParsePGPKey(pgpKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	buf := new(bytes.Buffer)
+	w, err := openpgp.Encrypt(buf, []*openpgp.Entity{entity}, nil, nil, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to encrypt value with PGP key: %v", err)
+	}
+
+	if _, err := w.Write([]byte(value)); err != nil {
+		return "", "", fmt.Errorf("unable to encrypt value with PGP key: %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return "", "", fmt.Errorf("unable to encrypt value with PGP key: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), fmt.Sprintf("%x", entity.PrimaryKey.Fingerprint), nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EncryptWithPGPKey(t *testing.T) {
+	r := require.New(t)
+
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", &packet.Config{RSABits: 1024})
+	r.NoError(err)
+
+	publicKey := new(bytes.Buffer)
+	r.NoError(entity.Serialize(publicKey))
+
+	armoredPublicKey := new(bytes.Buffer)
+	w, err := armor.Encode(armoredPublicKey, openpgp.PublicKeyType, nil)
+	r.NoError(err)
+	r.NoError(entity.Serialize(w))
+	r.NoError(w.Close())
+
+	tcs := map[string]struct {
+		pgpKey    string
+		expectErr bool
+	}{
+		"base64 encoded key": {
+			pgpKey: base64.StdEncoding.EncodeToString(publicKey.Bytes()),
+		},
+		"ASCII armored key": {
+			pgpKey: armoredPublicKey.String(),
+		},
+		"keybase reference": {
+			pgpKey:    "keybase:test",
+			expectErr: true,
+		},
+		"invalid base64": {
+			pgpKey:    "not base64!",
+			expectErr: true,
+		},
+		"not a key": {
+			pgpKey:    base64.StdEncoding.EncodeToString([]byte("not a key")),
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			encrypted, fingerprint, err := EncryptWithPGPKey(tc.pgpKey, "secret")
+			if tc.expectErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(fmt.Sprintf("%x", entity.PrimaryKey.Fingerprint), fingerprint)
+
+			ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+			r.NoError(err)
+
+			md, err := openpgp.ReadMessage(bytes.NewReader(ciphertext), openpgp.EntityList{entity}, nil, nil)
+			r.NoError(err)
+
+			plaintext, err := ioutil.ReadAll(md.UnverifiedBody)
+			r.NoError(err)
+			r.Equal("secret", string(plaintext))
+		})
+	}
+}
+
+func Test_ResolvePGPKey(t *testing.T) {
+	keybaseKeys := map[string]string{
+		"test": "exported-key",
+	}
+
+	tcs := map[string]struct {
+		pgpKey      string
+		expected    string
+		expectedErr string
+	}{
+		"PGP key": {
+			pgpKey:   "base64-key",
+			expected: "base64-key",
+		},
+		"keybase reference": {
+			pgpKey:   "keybase:test",
+			expected: "exported-key",
+		},
+		"unknown keybase user": {
+			pgpKey:      "keybase:other",
+			expectedErr: `no public key for keybase user "other"`,
+		},
+		"keybase reference without username": {
+			pgpKey:      "keybase:",
+			expectedErr: `keybase reference "keybase:" has no username`,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			key, err := ResolvePGPKey(tc.pgpKey, keybaseKeys)
+			if tc.expectedErr != "" {
+				r.Error(err)
+				r.Contains(err.Error(), tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tc.expected, key)
+		})
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultAgentCloudInitTimeoutDuration is the default timeout
+// for reading the agent cloud-init document.
+var defaultAgentCloudInitTimeoutDuration = time.Minute * 5
+
+// agentSystemdUnitPath is the path the systemd unit of the agent is written to by cloud-init.
+// It takes precedence over the unit installed by the Consul package.
+const agentSystemdUnitPath = "/etc/systemd/system/consul.service"
+
+// cloudConfig is the subset of the cloud-init cloud-config format used to provision an agent.
+type cloudConfig struct {
+	WriteFiles []cloudConfigFile `yaml:"write_files"`
+	RunCmd     []string          `yaml:"runcmd"`
+}
+
+// cloudConfigFile is a file written by the write_files module of cloud-init.
+type cloudConfigFile struct {
+	Path        string `yaml:"path"`
+	Content     string `yaml:"content"`
+	Owner       string `yaml:"owner"`
+	Permissions string `yaml:"permissions"`
+}
+
+// dataSourceAgentCloudInit is the data source for the cloud-init document
+// which provisions a Consul client agent on a VM.
+func dataSourceAgentCloudInit() *schema.Resource {
+	return &schema.Resource{
+		Description: "The agent cloud-init data source provides a cloud-init document, suitable for the `custom_data` of Azure VMs and VM scale sets, which installs a Consul client agent and joins it to the cluster on boot.",
+		ReadContext: dataSourceAgentCloudInitRead,
+		Timeouts: &schema.ResourceTimeout{
+			Default: &defaultAgentCloudInitTimeoutDuration,
+		},
+		Schema: map[string]*schema.Schema{
+			// Required inputs
+			"resource_group_name": {
+				Description:      "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateResourceGroupName,
+			},
+			"managed_application_name": {
+				Description:      "The name of the HCS Azure Managed Application.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateSlugID,
+			},
+			// Optional inputs
+			"acl_agent_token": {
+				Description: "The ACL token of the agent. If not specified, the config contains the `" + aclAgentTokenPlaceholder + "` placeholder, and the token can be set after boot with `consul acl set-agent-token agent`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"config_dir": {
+				Description:      "The directory from which the agent loads its config and CA file.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "/etc/consul.d",
+				ValidateDiagFunc: validateAbsolutePath,
+			},
+			"data_dir": {
+				Description:      "The directory in which the agent stores its state.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "/opt/consul",
+				ValidateDiagFunc: validateAbsolutePath,
+			},
+			"package_repository_url": {
+				Description:      "The URL of the APT repository from which the Consul package is installed.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "https://apt.releases.hashicorp.com",
+				ValidateDiagFunc: validateURL,
+			},
+			"package_repository_key_url": {
+				Description:      "The URL of the PGP key with which the packages of `package_repository_url` are signed.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "https://apt.releases.hashicorp.com/gpg",
+				ValidateDiagFunc: validateURL,
+			},
+			"consul_package_version": {
+				Description:      "The version of the Consul package to install, e.g. `1.9.5`. If not specified, the latest version of the repository is installed.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validatePackageVersion,
+			},
+			"gzip": {
+				Description: "Denotes that the rendered document should be gzip compressed. This requires `base64_encode`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"base64_encode": {
+				Description: "Denotes that the rendered document should be Base64 encoded, as expected by the `custom_data` of Azure VMs.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"multipart": {
+				Description: "Denotes that the cloud-config should be wrapped in a MIME multi-part archive, so that it can be combined with other cloud-init parts.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			// Computed outputs
+			"cloud_config": {
+				Description: "The cloud-config document, before the `multipart`, `gzip` and `base64_encode` options are applied. This contains the gossip encryption key.",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"rendered": {
+				Description: "The cloud-init document with the `multipart`, `gzip` and `base64_encode` options applied. This contains the gossip encryption key.",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// dataSourceAgentCloudInitRead retrieves the Consul config of the cluster and renders
+// the cloud-init document which provisions a Consul client agent.
+func dataSourceAgentCloudInitRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	gzipEnabled := d.Get("gzip").(bool)
+	base64Encode := d.Get("base64_encode").(bool)
+	if gzipEnabled && !base64Encode {
+		return diag.Errorf("`base64_encode` must be enabled when `gzip` is enabled, since Terraform strings must be valid UTF-8")
+	}
+
+	managedAppID, consulConfig, diags := fetchAgentConsulConfig(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	configDir := d.Get("config_dir").(string)
+	caFilePath := path.Join(configDir, "ca.pem")
+
+	config := newAgentConfig(consulConfig, d.Get("data_dir").(string), caFilePath, d.Get("acl_agent_token").(string))
+
+	configJSON, err := config.JSON()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	document, err := generateAgentCloudConfig(agentCloudConfigInput{
+		ConfigDir:               configDir,
+		DataDir:                 config.DataDir,
+		ConfigJSON:              configJSON,
+		CAFile:                  consulConfig.CaFile,
+		CAFilePath:              caFilePath,
+		PackageRepositoryURL:    d.Get("package_repository_url").(string),
+		PackageRepositoryKeyURL: d.Get("package_repository_key_url").(string),
+		ConsulPackageVersion:    d.Get("consul_package_version").(string),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("cloud_config", document)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rendered, err := renderCloudInit(document, d.Get("multipart").(bool), gzipEnabled, base64Encode)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("rendered", rendered)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(managedAppID + "/agent-cloud-init")
+
+	return nil
+}
+
+// agentCloudConfigInput holds the inputs of generateAgentCloudConfig.
+type agentCloudConfigInput struct {
+	ConfigDir               string
+	DataDir                 string
+	ConfigJSON              string
+	CAFile                  string
+	CAFilePath              string
+	PackageRepositoryURL    string
+	PackageRepositoryKeyURL string
+	ConsulPackageVersion    string
+}
+
+// generateAgentCloudConfig generates the cloud-config document which installs the Consul package,
+// writes the agent config, CA file and systemd unit, and starts the agent.
+func generateAgentCloudConfig(input agentCloudConfigInput) (string, error) {
+	consulPackage := "consul"
+	if input.ConsulPackageVersion != "" {
+		consulPackage = "consul=" + input.ConsulPackageVersion + "*"
+	}
+
+	// The Consul package creates the consul user, so the files are owned by root until it is installed.
+	config := cloudConfig{
+		WriteFiles: []cloudConfigFile{
+			{
+				Path:        path.Join(input.ConfigDir, "consul.json"),
+				Content:     input.ConfigJSON,
+				Owner:       "root:root",
+				Permissions: "0640",
+			},
+			{
+				Path:        input.CAFilePath,
+				Content:     input.CAFile,
+				Owner:       "root:root",
+				Permissions: "0644",
+			},
+			{
+				Path:        agentSystemdUnitPath,
+				Content:     generateAgentSystemdUnit("/usr/bin/consul", input.ConfigDir),
+				Owner:       "root:root",
+				Permissions: "0644",
+			},
+		},
+		RunCmd: []string{
+			fmt.Sprintf("curl -fsSL %s | gpg --dearmor -o /usr/share/keyrings/hashicorp-archive-keyring.gpg", shellQuote(input.PackageRepositoryKeyURL)),
+			fmt.Sprintf(`echo "deb [signed-by=/usr/share/keyrings/hashicorp-archive-keyring.gpg]" %s "$(lsb_release -cs)" main > /etc/apt/sources.list.d/hashicorp.list`, shellQuote(input.PackageRepositoryURL)),
+			"apt-get update",
+			fmt.Sprintf("DEBIAN_FRONTEND=noninteractive apt-get install -y -o Dpkg::Options::=--force-confold %s", shellQuote(consulPackage)),
+			fmt.Sprintf("mkdir -p %s", shellQuote(input.DataDir)),
+			fmt.Sprintf("chown -R consul:consul %s %s", shellQuote(input.ConfigDir), shellQuote(input.DataDir)),
+			"systemctl daemon-reload",
+			"systemctl enable --now consul",
+		},
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal cloud-config: %+v", err)
+	}
+
+	return "#cloud-config\n" + string(out), nil
+}
+
+// renderCloudInit optionally wraps the cloud-config document in a MIME multi-part archive,
+// gzip compresses it and Base64 encodes it.
+func renderCloudInit(document string, multipartEnabled, gzipEnabled, base64Encode bool) (string, error) {
+	rendered := []byte(document)
+
+	if multipartEnabled {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+
+		fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\nMIME-Version: 1.0\r\n\r\n", writer.Boundary())
+
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {`text/cloud-config; charset="utf-8"`},
+			"Content-Transfer-Encoding": {"7bit"},
+			"Content-Disposition":       {`attachment; filename="consul-agent.cfg"`},
+			"Mime-Version":              {"1.0"},
+		})
+		if err != nil {
+			return "", fmt.Errorf("unable to create cloud-init MIME part: %+v", err)
+		}
+
+		_, err = part.Write(rendered)
+		if err != nil {
+			return "", fmt.Errorf("unable to write cloud-init MIME part: %+v", err)
+		}
+
+		err = writer.Close()
+		if err != nil {
+			return "", fmt.Errorf("unable to close cloud-init MIME archive: %+v", err)
+		}
+
+		rendered = buf.Bytes()
+	}
+
+	if gzipEnabled {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+
+		_, err := writer.Write(rendered)
+		if err != nil {
+			return "", fmt.Errorf("unable to gzip cloud-init document: %+v", err)
+		}
+
+		err = writer.Close()
+		if err != nil {
+			return "", fmt.Errorf("unable to gzip cloud-init document: %+v", err)
+		}
+
+		rendered = buf.Bytes()
+	}
+
+	if base64Encode {
+		return base64.StdEncoding.EncodeToString(rendered), nil
+	}
+
+	return string(rendered), nil
+}
+
+// shellQuote quotes s as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestDataSourceAgentCloudInit(t *testing.T) {
+	r := require.New(t)
+
+	_, client := testFakeServer(t)
+	clusterState := testCreateCluster(t, client)
+
+	state := testReadDataSource(t, dataSourceAgentCloudInit(), map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"consul_package_version":   "1.9.5",
+		"gzip":                     true,
+		"base64_encode":            true,
+	}, client)
+
+	document := state.Attributes["cloud_config"]
+	r.True(strings.HasPrefix(document, "#cloud-config\n"))
+
+	var config cloudConfig
+	r.NoError(yaml.Unmarshal([]byte(document), &config))
+	r.Len(config.WriteFiles, 3)
+	r.Equal("/etc/consul.d/consul.json", config.WriteFiles[0].Path)
+	r.Contains(config.WriteFiles[0].Content, clusterState.Attributes["consul_gossip_encryption_key"])
+	r.Equal("/etc/consul.d/ca.pem", config.WriteFiles[1].Path)
+	r.Equal(clusterState.Attributes["consul_ca_pem"], config.WriteFiles[1].Content)
+	r.Equal(agentSystemdUnitPath, config.WriteFiles[2].Path)
+	r.Contains(config.RunCmd, `echo "deb [signed-by=/usr/share/keyrings/hashicorp-archive-keyring.gpg]" 'https://apt.releases.hashicorp.com' "$(lsb_release -cs)" main > /etc/apt/sources.list.d/hashicorp.list`)
+	r.Contains(config.RunCmd, "DEBIAN_FRONTEND=noninteractive apt-get install -y -o Dpkg::Options::=--force-confold 'consul=1.9.5*'")
+	r.Contains(config.RunCmd, "systemctl enable --now consul")
+
+	compressed, err := base64.StdEncoding.DecodeString(state.Attributes["rendered"])
+	r.NoError(err)
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	r.NoError(err)
+	rendered, err := ioutil.ReadAll(reader)
+	r.NoError(err)
+	r.Equal(document, string(rendered))
+}
+
+func Test_renderCloudInit(t *testing.T) {
+	r := require.New(t)
+
+	const document = "#cloud-config\nruncmd:\n- systemctl enable --now consul\n"
+
+	rendered, err := renderCloudInit(document, false, false, false)
+	r.NoError(err)
+	r.Equal(document, rendered)
+
+	rendered, err = renderCloudInit(document, false, false, true)
+	r.NoError(err)
+	r.Equal(base64.StdEncoding.EncodeToString([]byte(document)), rendered)
+
+	rendered, err = renderCloudInit(document, true, false, false)
+	r.NoError(err)
+
+	header, body := splitMIMEHeader(t, rendered)
+	mediaType, params, err := mime.ParseMediaType(header)
+	r.NoError(err)
+	r.Equal("multipart/mixed", mediaType)
+
+	reader := multipart.NewReader(strings.NewReader(body), params["boundary"])
+	part, err := reader.NextPart()
+	r.NoError(err)
+	r.Equal(`text/cloud-config; charset="utf-8"`, part.Header.Get("Content-Type"))
+	content, err := ioutil.ReadAll(part)
+	r.NoError(err)
+	r.Equal(document, string(content))
+}
+
+// splitMIMEHeader returns the Content-Type header and the body of a MIME multi-part archive.
+func splitMIMEHeader(t *testing.T, archive string) (string, string) {
+	r := require.New(t)
+
+	parts := strings.SplitN(archive, "\r\n\r\n", 2)
+	r.Len(parts, 2)
+
+	r.NotContains(strings.ReplaceAll(parts[0], "\r\n", ""), "\n", "header lines must end with CRLF")
+
+	for _, line := range strings.Split(parts[0], "\r\n") {
+		if strings.HasPrefix(line, "Content-Type: ") {
+			return strings.TrimPrefix(line, "Content-Type: "), parts[1]
+		}
+	}
+
+	r.FailNow("missing Content-Type header")
+	return "", ""
+}
@@ -0,0 +1,340 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+// defaultAgentConfigTimeoutDuration is the default timeout
+// for reading the agent config.
+var defaultAgentConfigTimeoutDuration = time.Minute * 5
+
+// aclAgentTokenPlaceholder is set as the ACL agent token of the agent config
+// when no token is specified, so that it can be substituted when the agent is provisioned.
+const aclAgentTokenPlaceholder = "<CONSUL_ACL_AGENT_TOKEN>"
+
+// agentSystemdUnitTemplate is the template used to generate the systemd
+// unit of a Consul client agent.
+//
+// see generateAgentSystemdUnit for details on the inputs passed in
+const agentSystemdUnitTemplate = `[Unit]
+Description="HashiCorp Consul - A service mesh solution"
+Documentation=https://www.consul.io/
+Requires=network-online.target
+After=network-online.target
+ConditionDirectoryNotEmpty=%s
+
+[Service]
+Type=notify
+User=consul
+Group=consul
+ExecStart=%s agent -config-dir=%s
+ExecReload=/bin/kill --signal HUP $MAINPID
+KillMode=process
+KillSignal=SIGTERM
+Restart=on-failure
+LimitNOFILE=65536
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// agentConfig is the configuration of a Consul client agent joining an HCS cluster.
+type agentConfig struct {
+	Datacenter            string                 `json:"datacenter"`
+	DataDir               string                 `json:"data_dir"`
+	Server                bool                   `json:"server"`
+	RetryJoin             []string               `json:"retry_join"`
+	Encrypt               string                 `json:"encrypt"`
+	EncryptVerifyIncoming bool                   `json:"encrypt_verify_incoming"`
+	EncryptVerifyOutgoing bool                   `json:"encrypt_verify_outgoing"`
+	VerifyOutgoing        bool                   `json:"verify_outgoing"`
+	VerifyServerHostname  bool                   `json:"verify_server_hostname"`
+	CAFile                string                 `json:"ca_file"`
+	AutoEncrypt           agentAutoEncryptConfig `json:"auto_encrypt"`
+	ACL                   agentACLConfig         `json:"acl"`
+	Ports                 agentPortsConfig       `json:"ports"`
+}
+
+// agentAutoEncryptConfig is the auto_encrypt stanza of the agent config.
+type agentAutoEncryptConfig struct {
+	TLS bool `json:"tls"`
+}
+
+// agentACLConfig is the acl stanza of the agent config.
+type agentACLConfig struct {
+	Enabled                bool                 `json:"enabled"`
+	DefaultPolicy          string               `json:"default_policy"`
+	EnableTokenPersistence bool                 `json:"enable_token_persistence"`
+	Tokens                 agentACLTokensConfig `json:"tokens"`
+}
+
+// agentACLTokensConfig is the acl.tokens stanza of the agent config.
+type agentACLTokensConfig struct {
+	Agent string `json:"agent"`
+}
+
+// agentPortsConfig is the ports stanza of the agent config.
+type agentPortsConfig struct {
+	GRPC int `json:"grpc"`
+}
+
+// dataSourceAgentConfig is the data source for the configuration of
+// a Consul client agent running on a VM.
+func dataSourceAgentConfig() *schema.Resource {
+	return &schema.Resource{
+		Description: "The agent config data source provides the configuration, CA file and systemd unit for a Consul client agent running on a VM.",
+		ReadContext: dataSourceAgentConfigRead,
+		Timeouts: &schema.ResourceTimeout{
+			Default: &defaultAgentConfigTimeoutDuration,
+		},
+		Schema: map[string]*schema.Schema{
+			// Required inputs
+			"resource_group_name": {
+				Description:      "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateResourceGroupName,
+			},
+			"managed_application_name": {
+				Description:      "The name of the HCS Azure Managed Application.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateSlugID,
+			},
+			// Optional inputs
+			"acl_agent_token": {
+				Description: "The ACL token of the agent. If not specified, the config contains the `" + aclAgentTokenPlaceholder + "` placeholder, which should be replaced when the agent is provisioned.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"config_dir": {
+				Description:      "The directory from which the agent loads its config and CA file.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "/etc/consul.d",
+				ValidateDiagFunc: validateAbsolutePath,
+			},
+			"data_dir": {
+				Description:      "The directory in which the agent stores its state.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "/opt/consul",
+				ValidateDiagFunc: validateAbsolutePath,
+			},
+			"consul_binary_path": {
+				Description:      "The path of the Consul binary that is run by the systemd unit.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "/usr/bin/consul",
+				ValidateDiagFunc: validateAbsolutePath,
+			},
+			// Computed outputs
+			"config_json": {
+				Description: "The agent config in JSON format. This contains the gossip encryption key.",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"config_hcl": {
+				Description: "The agent config in HCL format. This contains the gossip encryption key.",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"ca_file": {
+				Description: "The content of the CA file, in PEM format, referenced by the agent config.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"ca_file_path": {
+				Description: "The path of the CA file referenced by the agent config.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"systemd_unit": {
+				Description: "A systemd unit that runs the agent with the configs in `config_dir`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// dataSourceAgentConfigRead retrieves the Consul config of the cluster and renders the
+// configuration of a Consul client agent running on a VM.
+func dataSourceAgentConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	managedAppID, consulConfig, diags := fetchAgentConsulConfig(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	configDir := d.Get("config_dir").(string)
+	caFilePath := path.Join(configDir, "ca.pem")
+
+	config := newAgentConfig(consulConfig, d.Get("data_dir").(string), caFilePath, d.Get("acl_agent_token").(string))
+
+	configJSON, err := config.JSON()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("config_json", configJSON)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("config_hcl", config.HCL())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("ca_file", consulConfig.CaFile)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("ca_file_path", caFilePath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("systemd_unit", generateAgentSystemdUnit(d.Get("consul_binary_path").(string), configDir))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(managedAppID + "/agent-config")
+
+	return nil
+}
+
+// fetchAgentConsulConfig fetches the Managed Application identified by the resource_group_name and
+// managed_application_name inputs and the Consul config of its cluster. It returns the Managed Application ID
+// and the Consul config.
+func fetchAgentConsulConfig(ctx context.Context, d *schema.ResourceData, meta interface{}) (string, *clients.ConsulConfig, diag.Diagnostics) {
+	managedAppName := d.Get("managed_application_name").(string)
+	resourceGroupName := d.Get("resource_group_name").(string)
+
+	managedApp, err := meta.(*clients.Client).ManagedApplication.Get(ctx, resourceGroupName, managedAppName)
+	if err != nil {
+		if helper.IsAutoRestResponseCodeNotFound(managedApp.Response) {
+			return "", nil, diag.Errorf("HCS cluster not found (Managed Application %q) (Resource Group %q)", managedAppName, resourceGroupName)
+		}
+
+		return "", nil, diag.Errorf("unable to fetch HCS cluster (Resource Group Name %q) (Managed Application Name %q) (Correlation ID %q): %v",
+			resourceGroupName,
+			managedAppName,
+			meta.(*clients.Client).CorrelationRequestID,
+			err,
+		)
+	}
+
+	consulConfig, err := meta.(*clients.Client).CustomResourceProvider.GetConsulConfig(ctx, *managedApp.ManagedResourceGroupID, resourceGroupName)
+	if err != nil {
+		return "", nil, helper.ErrorDiagnostics(err, "unable to fetch Consul config (Resource Group Name %q) (Managed Application Name %q) (Correlation ID %q)",
+			resourceGroupName,
+			managedAppName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	return *managedApp.ID, consulConfig, nil
+}
+
+// newAgentConfig returns the config of a Consul client agent joining the cluster described by consulConfig.
+// If aclAgentToken is empty, the aclAgentTokenPlaceholder is used instead.
+func newAgentConfig(consulConfig *clients.ConsulConfig, dataDir, caFilePath, aclAgentToken string) agentConfig {
+	if aclAgentToken == "" {
+		aclAgentToken = aclAgentTokenPlaceholder
+	}
+
+	retryJoin := consulConfig.RetryJoin
+	if retryJoin == nil {
+		retryJoin = []string{}
+	}
+
+	return agentConfig{
+		Datacenter:            consulConfig.Datacenter,
+		DataDir:               dataDir,
+		RetryJoin:             retryJoin,
+		Encrypt:               consulConfig.GossipKey,
+		EncryptVerifyIncoming: true,
+		EncryptVerifyOutgoing: true,
+		VerifyOutgoing:        true,
+		VerifyServerHostname:  true,
+		CAFile:                caFilePath,
+		AutoEncrypt:           agentAutoEncryptConfig{TLS: true},
+		ACL: agentACLConfig{
+			Enabled:                true,
+			DefaultPolicy:          "deny",
+			EnableTokenPersistence: true,
+			Tokens:                 agentACLTokensConfig{Agent: aclAgentToken},
+		},
+		Ports: agentPortsConfig{GRPC: 8502},
+	}
+}
+
+// JSON returns the indented JSON encoding of the agent config.
+func (c agentConfig) JSON() (string, error) {
+	config, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal agent config: %+v", err)
+	}
+
+	return string(config) + "\n", nil
+}
+
+// HCL returns the HCL encoding of the agent config.
+func (c agentConfig) HCL() string {
+	retryJoin := make([]string, len(c.RetryJoin))
+	for i, address := range c.RetryJoin {
+		retryJoin[i] = strconv.Quote(address)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "datacenter = %s\n", strconv.Quote(c.Datacenter))
+	fmt.Fprintf(&b, "data_dir = %s\n", strconv.Quote(c.DataDir))
+	fmt.Fprintf(&b, "server = %t\n", c.Server)
+	fmt.Fprintf(&b, "retry_join = [%s]\n", strings.Join(retryJoin, ", "))
+	fmt.Fprintf(&b, "encrypt = %s\n", strconv.Quote(c.Encrypt))
+	fmt.Fprintf(&b, "encrypt_verify_incoming = %t\n", c.EncryptVerifyIncoming)
+	fmt.Fprintf(&b, "encrypt_verify_outgoing = %t\n", c.EncryptVerifyOutgoing)
+	fmt.Fprintf(&b, "verify_outgoing = %t\n", c.VerifyOutgoing)
+	fmt.Fprintf(&b, "verify_server_hostname = %t\n", c.VerifyServerHostname)
+	fmt.Fprintf(&b, "ca_file = %s\n", strconv.Quote(c.CAFile))
+	b.WriteString("\nauto_encrypt {\n")
+	fmt.Fprintf(&b, "  tls = %t\n", c.AutoEncrypt.TLS)
+	b.WriteString("}\n\nacl {\n")
+	fmt.Fprintf(&b, "  enabled = %t\n", c.ACL.Enabled)
+	fmt.Fprintf(&b, "  default_policy = %s\n", strconv.Quote(c.ACL.DefaultPolicy))
+	fmt.Fprintf(&b, "  enable_token_persistence = %t\n", c.ACL.EnableTokenPersistence)
+	b.WriteString("\n  tokens {\n")
+	fmt.Fprintf(&b, "    agent = %s\n", strconv.Quote(c.ACL.Tokens.Agent))
+	b.WriteString("  }\n}\n\nports {\n")
+	fmt.Fprintf(&b, "  grpc = %d\n", c.Ports.GRPC)
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// generateAgentSystemdUnit generates the systemd unit of a Consul client agent
+// which runs consulBinaryPath with the configs in configDir.
+func generateAgentSystemdUnit(consulBinaryPath, configDir string) string {
+	return fmt.Sprintf(agentSystemdUnitTemplate, configDir, consulBinaryPath, configDir)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
 )
 
 // agentConfigKubernetesSecretTemplate is the template used to generate a
@@ -84,11 +85,10 @@ func dataSourceAgentConfigKubernetesSecretRead(ctx context.Context, d *schema.Re
 
 	config, err := meta.(*clients.Client).CustomResourceProvider.GetConsulConfig(ctx, *managedApp.ManagedResourceGroupID, resourceGroupName)
 	if err != nil {
-		return diag.Errorf("unable to fetch Consul config (Resource Group Name %q) (Managed Application Name %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to fetch Consul config (Resource Group Name %q) (Managed Application Name %q) (Correlation ID %q)",
 			resourceGroupName,
 			managedAppName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
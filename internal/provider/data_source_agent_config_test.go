@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+)
+
+func TestDataSourceAgentConfig(t *testing.T) {
+	r := require.New(t)
+
+	_, client := testFakeServer(t)
+	clusterState := testCreateCluster(t, client)
+
+	state := testReadDataSource(t, dataSourceAgentConfig(), map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"config_dir":               "/etc/consul",
+	}, client)
+
+	var config agentConfig
+	r.NoError(json.Unmarshal([]byte(state.Attributes["config_json"]), &config))
+	r.Equal("hcs-test", config.Datacenter)
+	r.Equal("/opt/consul", config.DataDir)
+	r.Equal([]string{clusterState.Attributes["consul_retry_join.0"]}, config.RetryJoin)
+	r.Equal(clusterState.Attributes["consul_gossip_encryption_key"], config.Encrypt)
+	r.Equal("/etc/consul/ca.pem", config.CAFile)
+	r.True(config.AutoEncrypt.TLS)
+	r.Equal(aclAgentTokenPlaceholder, config.ACL.Tokens.Agent)
+
+	r.Contains(state.Attributes["config_hcl"], `datacenter = "hcs-test"`)
+	r.Equal(clusterState.Attributes["consul_ca_pem"], state.Attributes["ca_file"])
+	r.Equal("/etc/consul/ca.pem", state.Attributes["ca_file_path"])
+	r.Contains(state.Attributes["systemd_unit"], "ExecStart=/usr/bin/consul agent -config-dir=/etc/consul\n")
+}
+
+func Test_agentConfigHCL(t *testing.T) {
+	r := require.New(t)
+
+	config := newAgentConfig(&clients.ConsulConfig{
+		GossipKey:  "Z29zc2lwLWtleQ==",
+		Datacenter: "dc1",
+		RetryJoin:  []string{"dc1.private.consul.example.com", "10.0.0.4"},
+	}, "/opt/consul", "/etc/consul.d/ca.pem", "agent-token")
+
+	r.Equal(`datacenter = "dc1"
+data_dir = "/opt/consul"
+server = false
+retry_join = ["dc1.private.consul.example.com", "10.0.0.4"]
+encrypt = "Z29zc2lwLWtleQ=="
+encrypt_verify_incoming = true
+encrypt_verify_outgoing = true
+verify_outgoing = true
+verify_server_hostname = true
+ca_file = "/etc/consul.d/ca.pem"
+
+auto_encrypt {
+  tls = true
+}
+
+acl {
+  enabled = true
+  default_policy = "deny"
+  enable_token_persistence = true
+
+  tokens {
+    agent = "agent-token"
+  }
+}
+
+ports {
+  grpc = 8502
+}
+`, config.HCL())
+}
@@ -6,11 +6,16 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
 
 	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
 	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
@@ -20,40 +25,184 @@ import (
 // for reading the agent Helm config.
 var defaultAgentHelmConfigTimeoutDuration = time.Minute * 5
 
-// helmConfigTemplate is the template used to generate a helm
-// config for an AKS cluster based on given inputs.
-//
-// see generateHelmConfig for details on the inputs passed in
-const helmConfigTemplate = `global:
-  enabled: false
-  name: consul
-  datacenter: %s
-  acls:
-    manageSystemACLs: true
-    bootstrapToken:
-      secretName: %s-bootstrap-token
-      secretKey: token
-  gossipEncryption:
-    secretName: %s-hcs
-    secretKey: gossipEncryptionKey
-  tls:
-    enabled: true
-    enableAutoEncrypt: true
-    caCert:
-      secretName: %s-hcs
-      secretKey: caCert
-externalServers:
-  enabled: true
-  hosts: %s
-  httpsPort: 443
-  useSystemRoots: true
-  k8sAuthMethodHost: https://%s:443
-client:
-  enabled: true
-  exposeGossipPorts: %t
-  join: %s
-connectInject:
-  enabled: true`
+// defaultHelmChartVersion is the consul-k8s Helm chart version the Helm values are generated
+// for if no chart_version is specified.
+const defaultHelmChartVersion = "0.31.1"
+
+// supportedHelmChartVersions is the constraint of the consul-k8s Helm chart versions
+// the Helm values can be generated for. Chart 1.0.0 removed the client agents which
+// join HCS clusters.
+const supportedHelmChartVersions = ">= 0.28.0, < 1.0.0"
+
+// helmTransparentProxyChartVersion is the first consul-k8s Helm chart version with transparent proxy support,
+// which it enables by default.
+var helmTransparentProxyChartVersion = version.Must(version.NewVersion("0.32.0"))
+
+// helmMetricsChartVersion is the first consul-k8s Helm chart version with the global, connectInject
+// and ui metrics values.
+var helmMetricsChartVersion = version.Must(version.NewVersion("0.31.0"))
+
+// helmGatewayServiceTypes are the Kubernetes service types of mesh and ingress gateways.
+var helmGatewayServiceTypes = []string{"ClusterIP", "NodePort", "LoadBalancer"}
+
+// helmValues are the consul-k8s Helm chart values for Consul client agents joining an HCS cluster.
+type helmValues struct {
+	Global              helmGlobalValues          `yaml:"global"`
+	ExternalServers     helmExternalServersValues `yaml:"externalServers"`
+	Client              helmClientValues          `yaml:"client"`
+	ConnectInject       helmConnectInjectValues   `yaml:"connectInject"`
+	Controller          *helmEnabledValues        `yaml:"controller,omitempty"`
+	MeshGateway         *helmMeshGatewayValues    `yaml:"meshGateway,omitempty"`
+	IngressGateways     *helmGatewaysValues       `yaml:"ingressGateways,omitempty"`
+	TerminatingGateways *helmGatewaysValues       `yaml:"terminatingGateways,omitempty"`
+	UI                  *helmUIValues             `yaml:"ui,omitempty"`
+}
+
+// helmGlobalValues are the global values of the Helm chart.
+type helmGlobalValues struct {
+	Enabled          bool               `yaml:"enabled"`
+	Name             string             `yaml:"name"`
+	Datacenter       string             `yaml:"datacenter"`
+	ACLs             helmACLsValues     `yaml:"acls"`
+	GossipEncryption helmSecretKeyRef   `yaml:"gossipEncryption"`
+	TLS              helmTLSValues      `yaml:"tls"`
+	Metrics          *helmMetricsValues `yaml:"metrics,omitempty"`
+}
+
+// helmMetricsValues are the global.metrics values of the Helm chart.
+type helmMetricsValues struct {
+	Enabled                   bool   `yaml:"enabled"`
+	EnableAgentMetrics        bool   `yaml:"enableAgentMetrics"`
+	AgentMetricsRetentionTime string `yaml:"agentMetricsRetentionTime"`
+	EnableGatewayMetrics      bool   `yaml:"enableGatewayMetrics"`
+}
+
+// helmACLsValues are the global.acls values of the Helm chart.
+type helmACLsValues struct {
+	ManageSystemACLs bool             `yaml:"manageSystemACLs"`
+	BootstrapToken   helmSecretKeyRef `yaml:"bootstrapToken"`
+}
+
+// helmTLSValues are the global.tls values of the Helm chart.
+type helmTLSValues struct {
+	Enabled           bool             `yaml:"enabled"`
+	EnableAutoEncrypt bool             `yaml:"enableAutoEncrypt"`
+	CACert            helmSecretKeyRef `yaml:"caCert"`
+}
+
+// helmSecretKeyRef references a key of a Kubernetes secret.
+type helmSecretKeyRef struct {
+	SecretName string `yaml:"secretName"`
+	SecretKey  string `yaml:"secretKey"`
+}
+
+// helmExternalServersValues are the externalServers values of the Helm chart.
+type helmExternalServersValues struct {
+	Enabled           bool     `yaml:"enabled"`
+	Hosts             []string `yaml:"hosts"`
+	HTTPSPort         int      `yaml:"httpsPort"`
+	UseSystemRoots    bool     `yaml:"useSystemRoots"`
+	K8sAuthMethodHost string   `yaml:"k8sAuthMethodHost"`
+}
+
+// helmClientValues are the client values of the Helm chart.
+type helmClientValues struct {
+	Enabled           bool     `yaml:"enabled"`
+	ExposeGossipPorts bool     `yaml:"exposeGossipPorts"`
+	Join              []string `yaml:"join"`
+}
+
+// helmConnectInjectValues are the connectInject values of the Helm chart.
+type helmConnectInjectValues struct {
+	Enabled          bool                        `yaml:"enabled"`
+	TransparentProxy *helmTransparentProxyValues `yaml:"transparentProxy,omitempty"`
+	Metrics          *helmConnectMetricsValues   `yaml:"metrics,omitempty"`
+}
+
+// helmConnectMetricsValues are the connectInject.metrics values of the Helm chart.
+type helmConnectMetricsValues struct {
+	DefaultEnabled       bool `yaml:"defaultEnabled"`
+	DefaultEnableMerging bool `yaml:"defaultEnableMerging"`
+}
+
+// helmEnabledValues are the values of a Helm chart component which is only enabled.
+type helmEnabledValues struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// helmMeshGatewayValues are the meshGateway values of the Helm chart.
+type helmMeshGatewayValues struct {
+	Enabled  bool              `yaml:"enabled"`
+	Replicas int               `yaml:"replicas"`
+	Service  helmServiceValues `yaml:"service"`
+}
+
+// helmGatewaysValues are the ingressGateways or terminatingGateways values of the Helm chart.
+type helmGatewaysValues struct {
+	Enabled  bool                `yaml:"enabled"`
+	Gateways []helmGatewayValues `yaml:"gateways"`
+}
+
+// helmGatewayValues are the values of a single ingress or terminating gateway of the Helm chart.
+type helmGatewayValues struct {
+	Name     string             `yaml:"name"`
+	Replicas int                `yaml:"replicas"`
+	Service  *helmServiceValues `yaml:"service,omitempty"`
+}
+
+// helmServiceValues are the values of the Kubernetes service of a gateway.
+type helmServiceValues struct {
+	Type string `yaml:"type"`
+}
+
+// helmUIValues are the ui values of the Helm chart.
+type helmUIValues struct {
+	Metrics helmUIMetricsValues `yaml:"metrics"`
+}
+
+// helmUIMetricsValues are the ui.metrics values of the Helm chart.
+type helmUIMetricsValues struct {
+	Enabled  bool   `yaml:"enabled"`
+	Provider string `yaml:"provider"`
+	BaseURL  string `yaml:"baseURL"`
+}
+
+// helmTransparentProxyValues are the connectInject.transparentProxy values of the Helm chart.
+type helmTransparentProxyValues struct {
+	DefaultEnabled bool `yaml:"defaultEnabled"`
+}
+
+// helmValuesInput holds the cluster and Kubernetes properties the Helm values are generated from.
+type helmValuesInput struct {
+	Name              string
+	Datacenter        string
+	KubernetesAPIHost string
+	RetryJoin         []string
+	ExposeGossipPorts bool
+	ConnectInject     bool
+	Controller        bool
+	// MeshGateway is nil if no mesh gateway is deployed.
+	MeshGateway         *helmGatewayInput
+	IngressGateways     []helmGatewayInput
+	TerminatingGateways []helmGatewayInput
+	// Metrics is nil if metrics are not enabled.
+	Metrics *helmMetricsInput
+}
+
+// helmGatewayInput holds the settings of a mesh, ingress or terminating gateway.
+type helmGatewayInput struct {
+	Name        string
+	Replicas    int
+	ServiceType string
+}
+
+// helmMetricsInput holds the metrics settings of the Helm values.
+type helmMetricsInput struct {
+	AgentMetrics              bool
+	AgentMetricsRetentionTime string
+	GatewayMetrics            bool
+	PrometheusBaseURL         string
+}
 
 // dataSourceAgentHelmConfig is the data source for the agent Helm
 // config for an HCS cluster.
@@ -78,18 +227,27 @@ func dataSourceAgentHelmConfig() *schema.Resource {
 				Required:         true,
 				ValidateDiagFunc: validateSlugID,
 			},
+			// Optional
 			"aks_cluster_name": {
-				Description:      "The name of the AKS cluster that will consume the Helm config.",
+				Description:      "The name of the AKS cluster that will consume the Helm config. The Kubernetes API host is the FQDN of the cluster, or its private FQDN for private clusters. Exactly one of `aks_cluster_name` and `kubernetes_api_host` must be specified.",
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
 				ValidateDiagFunc: validateStringNotEmpty,
+				ExactlyOneOf:     []string{"aks_cluster_name", "kubernetes_api_host"},
 			},
-			// Optional
 			"aks_resource_group": {
 				Description:      "The resource group name of the AKS cluster that will consume the Helm config. If not specified, it is defaulted to the value of `resource_group_name`.",
 				Type:             schema.TypeString,
 				Optional:         true,
 				ValidateDiagFunc: validateStringNotEmpty,
+				ConflictsWith:    []string{"kubernetes_api_host"},
+			},
+			"kubernetes_api_host": {
+				Description:      "The host of the API server of the Kubernetes cluster that will consume the Helm config, optionally with a port, e.g. `10.0.0.4:6443`. If no port is specified, port 443 is used. This allows generating the Helm config for Kubernetes clusters other than AKS.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateHostPort,
+				ExactlyOneOf:     []string{"aks_cluster_name", "kubernetes_api_host"},
 			},
 			"expose_gossip_ports": {
 				Description: "Denotes that the gossip ports should be exposed.",
@@ -97,12 +255,163 @@ func dataSourceAgentHelmConfig() *schema.Resource {
 				Optional:    true,
 				Default:     false,
 			},
+			"connect_inject": {
+				Description: "Denotes that Connect sidecar injection should be enabled.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"controller": {
+				Description: "Denotes that the controller, which manages Consul config entries with custom resources, should be enabled.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"mesh_gateway": {
+				Description: "Deploys mesh gateways, which are needed for the communication between WAN federated datacenters. Requires `connect_inject`.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"replicas": {
+							Description:      "The number of mesh gateway replicas.",
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          1,
+							ValidateDiagFunc: validateIntAtLeast(1),
+						},
+						"service_type": {
+							Description:      "The type of the Kubernetes service of the mesh gateways ('ClusterIP', 'NodePort' or 'LoadBalancer').",
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "LoadBalancer",
+							ValidateDiagFunc: validateStringInSlice(helmGatewayServiceTypes, false),
+						},
+					},
+				},
+			},
+			"ingress_gateway": {
+				Description: "Deploys an ingress gateway. Requires `connect_inject`.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description:      "The name of the ingress gateway.",
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validateSlugID,
+						},
+						"replicas": {
+							Description:      "The number of ingress gateway replicas.",
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          1,
+							ValidateDiagFunc: validateIntAtLeast(1),
+						},
+						"service_type": {
+							Description:      "The type of the Kubernetes service of the ingress gateway ('ClusterIP', 'NodePort' or 'LoadBalancer').",
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "ClusterIP",
+							ValidateDiagFunc: validateStringInSlice(helmGatewayServiceTypes, false),
+						},
+					},
+				},
+			},
+			"terminating_gateway": {
+				Description: "Deploys a terminating gateway. Requires `connect_inject`.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description:      "The name of the terminating gateway.",
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validateSlugID,
+						},
+						"replicas": {
+							Description:      "The number of terminating gateway replicas.",
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          1,
+							ValidateDiagFunc: validateIntAtLeast(1),
+						},
+					},
+				},
+			},
+			"metrics": {
+				Description: "Enables the Prometheus metrics of the Consul agents, gateways and Connect sidecars. Requires `chart_version` 0.31.0 or later.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"agent_metrics": {
+							Description: "Denotes that the metrics of the Consul client agents should be exposed.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+						},
+						"agent_metrics_retention_time": {
+							Description:      "How long the client agents retain their metrics, e.g. `1m`.",
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "1m",
+							ValidateDiagFunc: validateDuration,
+						},
+						"gateway_metrics": {
+							Description: "Denotes that the metrics of the gateways should be exposed.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+						},
+						"prometheus_base_url": {
+							Description:      "The URL of the Prometheus server the Consul UI reads service metrics from, e.g. `http://prometheus-server.monitoring`. If not specified, the UI metrics are not configured.",
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validateURL,
+						},
+					},
+				},
+			},
+			"chart_version": {
+				Description:      "The version of the consul-k8s Helm chart the values are generated for. Versions `" + supportedHelmChartVersions + "` are supported.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          defaultHelmChartVersion,
+				ValidateDiagFunc: validateHelmChartVersion,
+			},
+			"overrides": {
+				Description:      "Helm values in YAML format, e.g. from `yamlencode`, that are deep merged on top of the generated values. Maps are merged recursively, other values replace the generated ones and `null` removes a generated value.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateYAMLMap,
+			},
+			"set": {
+				Description: "Helm values to set after `overrides` are merged, keyed by their dot separated path, e.g. `client.grpc`. The values are parsed as YAML, so `true` and `3` are set as a boolean and a number.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			// Computed outputs
 			"config": {
 				Description: "The agent Helm config.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"values": {
+				Description: "The agent Helm values, keyed by their dot separated path, e.g. `global.datacenter`. List elements are keyed by their index, e.g. `client.join.0`.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 	}
 }
@@ -130,11 +439,113 @@ func dataSourceAgentHelmConfigRead(ctx context.Context, d *schema.ResourceData,
 
 	consulConfig, err := crpClient.GetConsulConfig(ctx, managedAppManagedResourceGroupID, resourceGroupName)
 	if err != nil {
-		return diag.Errorf("unable to fetch config for managed app: %v", err)
+		return helper.ErrorDiagnostics(err, "unable to fetch config for managed app")
+	}
+
+	kubernetesAPIHost, diags := agentHelmConfigKubernetesAPIHost(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	input := expandHelmValuesInput(d)
+	input.Name = managedAppName
+	input.Datacenter = consulConfig.Datacenter
+	input.KubernetesAPIHost = kubernetesAPIHost
+	input.RetryJoin = consulConfig.RetryJoin
+
+	if !input.ConnectInject && (input.MeshGateway != nil || len(input.IngressGateways) > 0 || len(input.TerminatingGateways) > 0) {
+		return diag.Errorf("`connect_inject` must be enabled to deploy mesh, ingress or terminating gateways")
+	}
+
+	values, err := generateHelmValues(input, d.Get("chart_version").(string), d.Get("overrides").(string), d.Get("set").(map[string]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	config, err := yaml.Marshal(values)
+	if err != nil {
+		return diag.Errorf("unable to marshal Helm values: %v", err)
+	}
+
+	if err := d.Set("config", string(config)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("values", flattenHelmValues(values)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(*app.ID + "/agent-helm-config")
+
+	return nil
+}
+
+// expandHelmValuesInput builds a helmValuesInput from the optional inputs of the agent Helm config data source.
+func expandHelmValuesInput(d *schema.ResourceData) helmValuesInput {
+	input := helmValuesInput{
+		ExposeGossipPorts: d.Get("expose_gossip_ports").(bool),
+		ConnectInject:     d.Get("connect_inject").(bool),
+		Controller:        d.Get("controller").(bool),
+	}
+
+	if v := d.Get("mesh_gateway").([]interface{}); len(v) > 0 && v[0] != nil {
+		gateway := v[0].(map[string]interface{})
+		input.MeshGateway = &helmGatewayInput{
+			Replicas:    gateway["replicas"].(int),
+			ServiceType: gateway["service_type"].(string),
+		}
+	}
+
+	for _, v := range d.Get("ingress_gateway").([]interface{}) {
+		gateway := v.(map[string]interface{})
+		input.IngressGateways = append(input.IngressGateways, helmGatewayInput{
+			Name:        gateway["name"].(string),
+			Replicas:    gateway["replicas"].(int),
+			ServiceType: gateway["service_type"].(string),
+		})
+	}
+
+	for _, v := range d.Get("terminating_gateway").([]interface{}) {
+		gateway := v.(map[string]interface{})
+		input.TerminatingGateways = append(input.TerminatingGateways, helmGatewayInput{
+			Name:     gateway["name"].(string),
+			Replicas: gateway["replicas"].(int),
+		})
+	}
+
+	// An empty metrics block enables the metrics with their defaults, so it is not read as nil
+	if v := d.Get("metrics").([]interface{}); len(v) > 0 {
+		metrics := map[string]interface{}{
+			"agent_metrics":                true,
+			"agent_metrics_retention_time": "1m",
+			"gateway_metrics":              true,
+			"prometheus_base_url":          "",
+		}
+		if v[0] != nil {
+			metrics = v[0].(map[string]interface{})
+		}
+
+		input.Metrics = &helmMetricsInput{
+			AgentMetrics:              metrics["agent_metrics"].(bool),
+			AgentMetricsRetentionTime: metrics["agent_metrics_retention_time"].(string),
+			GatewayMetrics:            metrics["gateway_metrics"].(bool),
+			PrometheusBaseURL:         metrics["prometheus_base_url"].(string),
+		}
+	}
+
+	return input
+}
+
+// agentHelmConfigKubernetesAPIHost returns the kubernetes_api_host input, or the FQDN of the AKS
+// cluster identified by the aks_cluster_name and aks_resource_group inputs. The private FQDN is
+// returned for private AKS clusters, which have no public FQDN.
+func agentHelmConfigKubernetesAPIHost(ctx context.Context, d *schema.ResourceData, meta interface{}) (string, diag.Diagnostics) {
+	if v, ok := d.GetOk("kubernetes_api_host"); ok {
+		return v.(string), nil
 	}
 
 	// default to resource group name if aks_resource_group not present
-	aksResourceGroup := resourceGroupName
+	aksResourceGroup := d.Get("resource_group_name").(string)
 	v, ok := d.GetOk("aks_resource_group")
 	if ok {
 		aksResourceGroup = v.(string)
@@ -148,43 +559,335 @@ func dataSourceAgentHelmConfigRead(ctx context.Context, d *schema.ResourceData,
 	if err != nil {
 		if helper.IsAutoRestResponseCodeNotFound(mcResp.Response) {
 			// No AKS cluster exists, so returning an error stating as such
-			return diag.Errorf("AKS cluster not found (Cluster name %q) (Resource Group %q)", aksClusterName, aksResourceGroup)
+			return "", diag.Errorf("AKS cluster not found (Cluster name %q) (Resource Group %q)", aksClusterName, aksResourceGroup)
 		}
 
-		return diag.Errorf("unable to check for presence of an existing AKS Cluster (Cluster name %q) (Resource Group %q): %v", aksClusterName, aksResourceGroup, err)
+		return "", diag.Errorf("unable to check for presence of an existing AKS Cluster (Cluster name %q) (Resource Group %q): %v", aksClusterName, aksResourceGroup, err)
 	}
 
-	exposeGossipPorts := d.Get("expose_gossip_ports").(bool)
+	if mcResp.ManagedClusterProperties != nil {
+		if mcResp.Fqdn != nil && *mcResp.Fqdn != "" {
+			return *mcResp.Fqdn, nil
+		}
 
-	if err := d.Set("config", generateHelmConfig(
-		managedAppName, consulConfig.Datacenter, *mcResp.Fqdn, consulConfig.RetryJoin, exposeGossipPorts)); err != nil {
-		return diag.FromErr(err)
+		if mcResp.PrivateFQDN != nil && *mcResp.PrivateFQDN != "" {
+			return *mcResp.PrivateFQDN, nil
+		}
 	}
 
-	d.SetId(*app.ID + "/agent-helm-config")
-
-	return nil
+	return "", diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("AKS cluster has no API server FQDN (Cluster name %q) (Resource Group %q)", aksClusterName, aksResourceGroup),
+			Detail:   "Neither the FQDN nor the private FQDN of the AKS cluster is set. Specify the host of the Kubernetes API server with `kubernetes_api_host` instead of `aks_cluster_name`.",
+		},
+	}
 }
 
-// generateHelmConfig will generate a helm config based on the passed in
-// name, data center, retry join, and fqdn.
-func generateHelmConfig(name, datacenter, fqdn string, retryJoin []string, exposeGossipPorts bool) string {
+// newHelmValues returns the typed Helm values for the chart version, generated from the input.
+func newHelmValues(input helmValuesInput, chartVersion *version.Version) helmValues {
 	// lowercase the name
-	lower := strings.ToLower(name)
-
-	// print retryJoin a double-quoted string safely escaped with Go syntax
-	rj := fmt.Sprintf("%q", retryJoin)
-
-	// replace any escaped double-quotes with single quotes
-	// this is to match the format the the HCS CLI is outputting
-	rj = strings.Replace(rj, "\"", "'", -1)
-
-	return fmt.Sprintf(helmConfigTemplate,
-		datacenter,
-		lower, lower, lower,
-		rj,
-		fqdn,
-		exposeGossipPorts,
-		rj,
-	)
+	lower := strings.ToLower(input.Name)
+
+	retryJoin := input.RetryJoin
+	if retryJoin == nil {
+		retryJoin = []string{}
+	}
+
+	values := helmValues{
+		Global: helmGlobalValues{
+			Enabled:    false,
+			Name:       "consul",
+			Datacenter: input.Datacenter,
+			ACLs: helmACLsValues{
+				ManageSystemACLs: true,
+				BootstrapToken: helmSecretKeyRef{
+					SecretName: lower + "-bootstrap-token",
+					SecretKey:  "token",
+				},
+			},
+			GossipEncryption: helmSecretKeyRef{
+				SecretName: lower + "-hcs",
+				SecretKey:  "gossipEncryptionKey",
+			},
+			TLS: helmTLSValues{
+				Enabled:           true,
+				EnableAutoEncrypt: true,
+				CACert: helmSecretKeyRef{
+					SecretName: lower + "-hcs",
+					SecretKey:  "caCert",
+				},
+			},
+		},
+		ExternalServers: helmExternalServersValues{
+			Enabled:           true,
+			Hosts:             retryJoin,
+			HTTPSPort:         443,
+			UseSystemRoots:    true,
+			K8sAuthMethodHost: "https://" + withDefaultPort(input.KubernetesAPIHost, "443"),
+		},
+		Client: helmClientValues{
+			Enabled:           true,
+			ExposeGossipPorts: input.ExposeGossipPorts,
+			Join:              retryJoin,
+		},
+		ConnectInject: helmConnectInjectValues{
+			Enabled: input.ConnectInject,
+		},
+	}
+
+	if input.Controller {
+		values.Controller = &helmEnabledValues{Enabled: true}
+	}
+
+	if input.MeshGateway != nil {
+		values.MeshGateway = &helmMeshGatewayValues{
+			Enabled:  true,
+			Replicas: input.MeshGateway.Replicas,
+			Service:  helmServiceValues{Type: input.MeshGateway.ServiceType},
+		}
+	}
+
+	if len(input.IngressGateways) > 0 {
+		values.IngressGateways = &helmGatewaysValues{Enabled: true}
+		for _, gateway := range input.IngressGateways {
+			values.IngressGateways.Gateways = append(values.IngressGateways.Gateways, helmGatewayValues{
+				Name:     gateway.Name,
+				Replicas: gateway.Replicas,
+				Service:  &helmServiceValues{Type: gateway.ServiceType},
+			})
+		}
+	}
+
+	if len(input.TerminatingGateways) > 0 {
+		values.TerminatingGateways = &helmGatewaysValues{Enabled: true}
+		for _, gateway := range input.TerminatingGateways {
+			values.TerminatingGateways.Gateways = append(values.TerminatingGateways.Gateways, helmGatewayValues{
+				Name:     gateway.Name,
+				Replicas: gateway.Replicas,
+			})
+		}
+	}
+
+	if input.Metrics != nil {
+		values.Global.Metrics = &helmMetricsValues{
+			Enabled:                   true,
+			EnableAgentMetrics:        input.Metrics.AgentMetrics,
+			AgentMetricsRetentionTime: input.Metrics.AgentMetricsRetentionTime,
+			EnableGatewayMetrics:      input.Metrics.GatewayMetrics,
+		}
+
+		if input.ConnectInject {
+			values.ConnectInject.Metrics = &helmConnectMetricsValues{
+				DefaultEnabled:       true,
+				DefaultEnableMerging: true,
+			}
+		}
+
+		if input.Metrics.PrometheusBaseURL != "" {
+			values.UI = &helmUIValues{
+				Metrics: helmUIMetricsValues{
+					Enabled:  true,
+					Provider: "prometheus",
+					BaseURL:  input.Metrics.PrometheusBaseURL,
+				},
+			}
+		}
+	}
+
+	// Transparent proxy requires Consul 1.10, which not all HCS clusters run,
+	// so it is not enabled by default for the charts which support it.
+	if chartVersion.GreaterThanOrEqual(helmTransparentProxyChartVersion) {
+		values.ConnectInject.TransparentProxy = &helmTransparentProxyValues{
+			DefaultEnabled: false,
+		}
+	}
+
+	return values
+}
+
+// generateHelmValues generates the Helm values for the chart version from the input,
+// deep merges the overrides YAML on top of them and sets the values of set.
+// The values are returned as a yaml.MapSlice, which preserves the order of the keys.
+func generateHelmValues(input helmValuesInput, chartVersion, overrides string, set map[string]interface{}) (yaml.MapSlice, error) {
+	v, err := version.NewVersion(chartVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart version %q: %v", chartVersion, err)
+	}
+
+	if input.Metrics != nil && v.LessThan(helmMetricsChartVersion) {
+		return nil, fmt.Errorf("metrics require chart version %s or later; got %s", helmMetricsChartVersion, chartVersion)
+	}
+
+	// Round trip the typed values so that they can be merged with the untyped overrides
+	out, err := yaml.Marshal(newHelmValues(input, v))
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal Helm values: %v", err)
+	}
+
+	var values yaml.MapSlice
+	err = yaml.Unmarshal(out, &values)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal Helm values: %v", err)
+	}
+
+	if overrides != "" {
+		var overrideValues yaml.MapSlice
+		err = yaml.Unmarshal([]byte(overrides), &overrideValues)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal Helm value overrides: %v", err)
+		}
+
+		values = mergeHelmValues(values, overrideValues)
+	}
+
+	// Set the values in a stable order
+	paths := make([]string, 0, len(set))
+	for path := range set {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		var value interface{}
+		err = yaml.Unmarshal([]byte(set[path].(string)), &value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse Helm value %q: %v", path, err)
+		}
+
+		values = setHelmValue(values, strings.Split(path, "."), normalizeHelmValue(value))
+	}
+
+	return values, nil
+}
+
+// mergeHelmValues deep merges the overrides on top of the values, like Helm merges values files.
+// Maps are merged recursively, other values are replaced and null values remove the key.
+func mergeHelmValues(values, overrides yaml.MapSlice) yaml.MapSlice {
+	merged := make(yaml.MapSlice, len(values))
+	copy(merged, values)
+
+	for _, override := range overrides {
+		i := indexHelmValue(merged, override.Key)
+
+		if override.Value == nil {
+			if i >= 0 {
+				merged = append(merged[:i], merged[i+1:]...)
+			}
+			continue
+		}
+
+		if i < 0 {
+			merged = append(merged, override)
+			continue
+		}
+
+		base, baseIsMap := merged[i].Value.(yaml.MapSlice)
+		overrideMap, overrideIsMap := override.Value.(yaml.MapSlice)
+		if baseIsMap && overrideIsMap {
+			merged[i].Value = mergeHelmValues(base, overrideMap)
+		} else {
+			merged[i].Value = override.Value
+		}
+	}
+
+	return merged
+}
+
+// setHelmValue sets the value at the path of keys, creating the intermediate maps
+// and replacing intermediate values which are not maps.
+func setHelmValue(values yaml.MapSlice, path []string, value interface{}) yaml.MapSlice {
+	if len(path) == 0 {
+		return values
+	}
+
+	if len(path) == 1 {
+		return mergeHelmValues(values, yaml.MapSlice{{Key: path[0], Value: value}})
+	}
+
+	child := yaml.MapSlice{}
+	if i := indexHelmValue(values, path[0]); i >= 0 {
+		if m, ok := values[i].Value.(yaml.MapSlice); ok {
+			child = m
+		}
+	}
+
+	return mergeHelmValues(values, yaml.MapSlice{{Key: path[0], Value: setHelmValue(child, path[1:], value)}})
+}
+
+// normalizeHelmValue converts the maps of a value decoded by yaml.Unmarshal into a yaml.MapSlice,
+// with the keys in sorted order, so that they can be merged.
+func normalizeHelmValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(yaml.MapSlice, 0, len(v))
+		for key, element := range v {
+			normalized = append(normalized, yaml.MapItem{Key: key, Value: normalizeHelmValue(element)})
+		}
+		sort.Slice(normalized, func(i, j int) bool {
+			return fmt.Sprint(normalized[i].Key) < fmt.Sprint(normalized[j].Key)
+		})
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, element := range v {
+			normalized[i] = normalizeHelmValue(element)
+		}
+		return normalized
+	default:
+		return v
+	}
+}
+
+// indexHelmValue returns the index of the key in the values, or -1 if it is not present.
+func indexHelmValue(values yaml.MapSlice, key interface{}) int {
+	for i, item := range values {
+		if fmt.Sprint(item.Key) == fmt.Sprint(key) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// flattenHelmValues flattens the Helm values into a map of their dot separated
+// paths to their string representation.
+func flattenHelmValues(values yaml.MapSlice) map[string]interface{} {
+	flattened := map[string]interface{}{}
+	flattenHelmValue("", values, flattened)
+	return flattened
+}
+
+// flattenHelmValue adds the value, or its elements if it is a map or list, to flattened.
+func flattenHelmValue(path string, value interface{}, flattened map[string]interface{}) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		for _, item := range v {
+			flattenHelmValue(join(fmt.Sprint(item.Key)), item.Value, flattened)
+		}
+	case []interface{}:
+		for i, element := range v {
+			flattenHelmValue(join(strconv.Itoa(i)), element, flattened)
+		}
+	case nil:
+		flattened[path] = ""
+	default:
+		flattened[path] = fmt.Sprint(v)
+	}
+}
+
+// withDefaultPort appends the port to the host if it does not specify a port.
+func withDefaultPort(host, port string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+
+	return net.JoinHostPort(strings.Trim(host, "[]"), port)
 }
@@ -0,0 +1,264 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestDataSourceAgentHelmConfig(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	clusterState := testCreateCluster(t, client)
+	server.AddManagedCluster(testResourceGroupName, "aks", "aks.hcp.westus2.azmk8s.io", "")
+
+	state := testReadDataSource(t, dataSourceAgentHelmConfig(), map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"aks_cluster_name":         "aks",
+		"overrides":                "client:\n  grpc: true\n",
+		"set": map[string]interface{}{
+			"connectInject.enabled": "false",
+		},
+	}, client)
+
+	var values map[string]interface{}
+	r.NoError(yaml.Unmarshal([]byte(state.Attributes["config"]), &values))
+	r.Equal(true, values["client"].(map[interface{}]interface{})["grpc"])
+
+	r.Equal("hcs-test", state.Attributes["values.global.datacenter"])
+	r.Equal("hcs-test-hcs", state.Attributes["values.global.gossipEncryption.secretName"])
+	r.Equal("https://aks.hcp.westus2.azmk8s.io:443", state.Attributes["values.externalServers.k8sAuthMethodHost"])
+	r.Equal(clusterState.Attributes["consul_retry_join.0"], state.Attributes["values.client.join.0"])
+	r.Equal("true", state.Attributes["values.client.grpc"])
+	r.Equal("false", state.Attributes["values.connectInject.enabled"])
+}
+
+func TestDataSourceAgentHelmConfig_gatewaysRequireConnectInject(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	testCreateCluster(t, client)
+	server.AddManagedCluster(testResourceGroupName, "aks", "aks.hcp.westus2.azmk8s.io", "")
+
+	res := dataSourceAgentHelmConfig()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"aks_cluster_name":         "aks",
+		"connect_inject":           false,
+		"mesh_gateway":             []interface{}{map[string]interface{}{}},
+	})
+	diags := res.ReadContext(context.Background(), d, client)
+	r.True(diags.HasError())
+}
+
+func TestDataSourceAgentHelmConfig_kubernetesAPIHost(t *testing.T) {
+	server, client := testFakeServer(t)
+	testCreateCluster(t, client)
+	server.AddManagedCluster(testResourceGroupName, "private-aks", "", "private-aks.privatelink.westus2.azmk8s.io")
+	server.AddManagedCluster(testResourceGroupName, "no-fqdn-aks", "", "")
+
+	tcs := map[string]struct {
+		config    map[string]interface{}
+		expected  string
+		expectErr bool
+	}{
+		"private AKS cluster": {
+			config:   map[string]interface{}{"aks_cluster_name": "private-aks"},
+			expected: "https://private-aks.privatelink.westus2.azmk8s.io:443",
+		},
+		"Kubernetes API host": {
+			config:   map[string]interface{}{"kubernetes_api_host": "10.0.0.4:6443"},
+			expected: "https://10.0.0.4:6443",
+		},
+		"AKS cluster without FQDN": {
+			config:    map[string]interface{}{"aks_cluster_name": "no-fqdn-aks"},
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			config := map[string]interface{}{
+				"resource_group_name":      testResourceGroupName,
+				"managed_application_name": "hcs-test",
+			}
+			for k, v := range tc.config {
+				config[k] = v
+			}
+
+			res := dataSourceAgentHelmConfig()
+			d := schema.TestResourceDataRaw(t, res.Schema, config)
+			diags := res.ReadContext(context.Background(), d, client)
+			if tc.expectErr {
+				r.True(diags.HasError())
+				return
+			}
+
+			r.False(diags.HasError(), "%v", diags)
+			r.Equal(tc.expected, d.Get("values").(map[string]interface{})["externalServers.k8sAuthMethodHost"])
+		})
+	}
+}
+
+func Test_generateHelmValues(t *testing.T) {
+	input := helmValuesInput{
+		Name:              "HCS-Test",
+		Datacenter:        "dc1",
+		KubernetesAPIHost: "aks.example.com",
+		RetryJoin:         []string{"dc1.private.consul.example.com"},
+		ConnectInject:     true,
+	}
+
+	tcs := map[string]struct {
+		input        func(input *helmValuesInput)
+		chartVersion string
+		overrides    string
+		set          map[string]interface{}
+		expected     map[string]string
+		absent       []string
+		expectedErr  string
+	}{
+		"default values": {
+			chartVersion: defaultHelmChartVersion,
+			expected: map[string]string{
+				"global.enabled":                        "false",
+				"global.acls.bootstrapToken.secretName": "hcs-test-bootstrap-token",
+				"externalServers.hosts.0":               "dc1.private.consul.example.com",
+				"externalServers.k8sAuthMethodHost":     "https://aks.example.com:443",
+				"client.exposeGossipPorts":              "false",
+				"connectInject.enabled":                 "true",
+			},
+			absent: []string{"connectInject.transparentProxy.defaultEnabled", "controller.enabled", "meshGateway.enabled", "global.metrics.enabled", "ui.metrics.enabled"},
+		},
+		"gateways and controller": {
+			input: func(input *helmValuesInput) {
+				input.Controller = true
+				input.MeshGateway = &helmGatewayInput{Replicas: 2, ServiceType: "LoadBalancer"}
+				input.IngressGateways = []helmGatewayInput{{Name: "ingress-gateway", Replicas: 1, ServiceType: "ClusterIP"}}
+				input.TerminatingGateways = []helmGatewayInput{{Name: "terminating-gateway", Replicas: 3}}
+			},
+			chartVersion: defaultHelmChartVersion,
+			expected: map[string]string{
+				"controller.enabled":                      "true",
+				"meshGateway.enabled":                     "true",
+				"meshGateway.replicas":                    "2",
+				"meshGateway.service.type":                "LoadBalancer",
+				"ingressGateways.enabled":                 "true",
+				"ingressGateways.gateways.0.name":         "ingress-gateway",
+				"ingressGateways.gateways.0.service.type": "ClusterIP",
+				"terminatingGateways.enabled":             "true",
+				"terminatingGateways.gateways.0.name":     "terminating-gateway",
+				"terminatingGateways.gateways.0.replicas": "3",
+			},
+			absent: []string{"terminatingGateways.gateways.0.service.type"},
+		},
+		"metrics": {
+			input: func(input *helmValuesInput) {
+				input.Metrics = &helmMetricsInput{
+					AgentMetrics:              true,
+					AgentMetricsRetentionTime: "5m",
+					PrometheusBaseURL:         "http://prometheus-server.monitoring",
+				}
+			},
+			chartVersion: defaultHelmChartVersion,
+			expected: map[string]string{
+				"global.metrics.enabled":                   "true",
+				"global.metrics.enableAgentMetrics":        "true",
+				"global.metrics.agentMetricsRetentionTime": "5m",
+				"global.metrics.enableGatewayMetrics":      "false",
+				"connectInject.metrics.defaultEnabled":     "true",
+				"ui.metrics.provider":                      "prometheus",
+				"ui.metrics.baseURL":                       "http://prometheus-server.monitoring",
+			},
+		},
+		"metrics require chart 0.31.0": {
+			input: func(input *helmValuesInput) {
+				input.Metrics = &helmMetricsInput{AgentMetrics: true}
+			},
+			chartVersion: "0.30.0",
+			expectedErr:  "metrics require chart version 0.31.0 or later; got 0.30.0",
+		},
+		"transparent proxy chart": {
+			chartVersion: "0.32.0",
+			expected: map[string]string{
+				"connectInject.transparentProxy.defaultEnabled": "false",
+			},
+		},
+		"overrides are deep merged": {
+			chartVersion: defaultHelmChartVersion,
+			overrides:    "global:\n  image: hashicorp/consul:1.9.5\n  tls:\n    enableAutoEncrypt: false\nexternalServers:\n  hosts: [consul.example.com]\nclient: null\n",
+			expected: map[string]string{
+				"global.image":                 "hashicorp/consul:1.9.5",
+				"global.tls.enabled":           "true",
+				"global.tls.enableAutoEncrypt": "false",
+				"global.datacenter":            "dc1",
+				"externalServers.hosts.0":      "consul.example.com",
+			},
+			absent: []string{"client.enabled", "client.join.0"},
+		},
+		"set values": {
+			chartVersion: defaultHelmChartVersion,
+			overrides:    "client:\n  grpc: false\n",
+			set: map[string]interface{}{
+				"client.grpc":               "true",
+				"client.resources.requests": "{memory: 100Mi}",
+				"global.name":               "consul-hcs",
+			},
+			expected: map[string]string{
+				"client.grpc":                      "true",
+				"client.resources.requests.memory": "100Mi",
+				"global.name":                      "consul-hcs",
+			},
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			input := input
+			if tc.input != nil {
+				tc.input(&input)
+			}
+
+			values, err := generateHelmValues(input, tc.chartVersion, tc.overrides, tc.set)
+			if tc.expectedErr != "" {
+				r.EqualError(err, tc.expectedErr)
+				return
+			}
+			r.NoError(err)
+
+			flattened := flattenHelmValues(values)
+			for path, expected := range tc.expected {
+				r.Equal(expected, flattened[path], path)
+			}
+			for _, path := range tc.absent {
+				r.NotContains(flattened, path)
+			}
+		})
+	}
+}
+
+func Test_mergeHelmValues_preservesOrder(t *testing.T) {
+	r := require.New(t)
+
+	merged := mergeHelmValues(
+		yaml.MapSlice{{Key: "global", Value: yaml.MapSlice{{Key: "name", Value: "consul"}}}, {Key: "client", Value: true}},
+		yaml.MapSlice{{Key: "client", Value: false}, {Key: "server", Value: yaml.MapSlice{{Key: "enabled", Value: false}}}},
+	)
+
+	out, err := yaml.Marshal(merged)
+	r.NoError(err)
+	r.Equal("global:\n  name: consul\nclient: false\nserver:\n  enabled: false\n", string(out))
+}
@@ -0,0 +1,291 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+// defaultBillingInfoTimeoutDuration is the default timeout for reading the billing info.
+var defaultBillingInfoTimeoutDuration = time.Minute * 5
+
+// billedUsageElem is the schema of the usage billed for a time period.
+var billedUsageElem = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"start": {
+			Description: "The start time of the time period.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"end": {
+			Description: "The end time of the time period.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"cost": {
+			Description: "The total cost billed for the time period.",
+			Type:        schema.TypeFloat,
+			Computed:    true,
+		},
+		"plans": {
+			Description: "The hours consumed on each plan during the time period.",
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"plan": {
+						Description: "The name of the billing plan.",
+						Type:        schema.TypeString,
+						Computed:    true,
+					},
+					"hours": {
+						Description: "The number of hours consumed on the plan.",
+						Type:        schema.TypeFloat,
+						Computed:    true,
+					},
+				},
+			},
+		},
+	},
+}
+
+// dataSourceBillingInfo is the data source for the current billing settings and usage of an HCS cluster.
+func dataSourceBillingInfo() *schema.Resource {
+	return &schema.Resource{
+		Description: "The billing info data source provides the current billing plan, prices and usage of an HCS cluster.",
+		ReadContext: dataSourceBillingInfoRead,
+		Timeouts: &schema.ResourceTimeout{
+			Default: &defaultBillingInfoTimeoutDuration,
+		},
+		Schema: map[string]*schema.Schema{
+			// Required inputs
+			"resource_group_name": {
+				Description:      "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateResourceGroupName,
+			},
+			"managed_application_name": {
+				Description:      "The name of the HCS Azure Managed Application.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateSlugID,
+			},
+			// Computed outputs
+			"plan": {
+				Description: "The name of the active billing plan, e.g. 'annual'.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"active_since": {
+				Description: "The time since when the active billing plan is in effect.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"hourly_price": {
+				Description: "The flat hourly fee billed for the active plan, independent of the other billing dimensions.",
+				Type:        schema.TypeFloat,
+				Computed:    true,
+			},
+			"dimensions": {
+				Description: "The billing dimensions of the active plan and their tiered prices.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "The name of the billing dimension.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"minimum": {
+							Description: "The minimum number of billed units of the dimension.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"tiers": {
+							Description: "The pricing tiers of the dimension.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"label": {
+										Description: "The description of the pricing tier.",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"unit_price": {
+										Description: "The price of a single unit in the pricing tier.",
+										Type:        schema.TypeFloat,
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"usage_today": {
+				Description: "The usage billed today.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        billedUsageElem,
+			},
+			"usage_this_month": {
+				Description: "The usage billed this month.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        billedUsageElem,
+			},
+		},
+	}
+}
+
+// dataSourceBillingInfoRead retrieves the billing settings and usage of the HCS cluster.
+func dataSourceBillingInfoRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceGroupName := d.Get("resource_group_name").(string)
+	managedAppName := d.Get("managed_application_name").(string)
+
+	managedAppClient := meta.(*clients.Client).ManagedApplication
+	app, err := managedAppClient.Get(ctx, resourceGroupName, managedAppName)
+	if err != nil {
+		if helper.IsAutoRestResponseCodeNotFound(app.Response) {
+			return diag.Errorf("unable to fetch billing info; HCS cluster not found (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+				managedAppName,
+				resourceGroupName,
+				meta.(*clients.Client).CorrelationRequestID,
+			)
+		}
+
+		return diag.Errorf("unable to check for presence of an existing HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+			err,
+		)
+	}
+
+	resp, err := meta.(*clients.Client).CustomResourceProvider.GetBillingInfo(ctx, *app.ManagedResourceGroupID)
+	if err != nil {
+		return helper.ErrorDiagnostics(err, "unable to fetch billing info (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	settings := resp.CurrentSettings
+	if settings == nil {
+		settings = &models.HashicorpCloudConsulamaAmaBillingSettings{}
+	}
+
+	if err := d.Set("plan", settings.Plan); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("active_since", settings.ActiveSince); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var hourlyPrice float64
+	var dimensions []*models.HashicorpCloudConsulamaAmaBillingSettingsPricesPriceDimension
+	if settings.Prices != nil {
+		hourlyPrice = settings.Prices.Hourly
+		dimensions = settings.Prices.Dimensions
+	}
+
+	if err := d.Set("hourly_price", hourlyPrice); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("dimensions", flattenBillingPriceDimensions(dimensions)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var today, thisMonth *models.HashicorpCloudConsulamaAmaBilledUsage
+	if resp.Usage != nil && resp.Usage.TimePeriod != nil {
+		today = resp.Usage.TimePeriod.Today
+		thisMonth = resp.Usage.TimePeriod.ThisMonth
+	}
+
+	if err := d.Set("usage_today", flattenBilledUsage(today)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("usage_this_month", flattenBilledUsage(thisMonth)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(*app.ID + "/billing-info")
+
+	return nil
+}
+
+// flattenBillingPriceDimensions converts the price dimensions of a billing plan to their schema representation.
+func flattenBillingPriceDimensions(dimensions []*models.HashicorpCloudConsulamaAmaBillingSettingsPricesPriceDimension) []interface{} {
+	flattened := make([]interface{}, 0, len(dimensions))
+
+	for _, dimension := range dimensions {
+		if dimension == nil {
+			continue
+		}
+
+		tiers := make([]interface{}, 0, len(dimension.Tiers))
+		for _, tier := range dimension.Tiers {
+			if tier == nil {
+				continue
+			}
+
+			tiers = append(tiers, map[string]interface{}{
+				"label":      tier.Label,
+				"unit_price": tier.UnitPrice,
+			})
+		}
+
+		flattened = append(flattened, map[string]interface{}{
+			"name":    dimension.Name,
+			"minimum": int(dimension.Minimum),
+			"tiers":   tiers,
+		})
+	}
+
+	return flattened
+}
+
+// flattenBilledUsage converts the usage billed for a time period to its schema representation.
+// An empty list is returned if no usage is available.
+func flattenBilledUsage(usage *models.HashicorpCloudConsulamaAmaBilledUsage) []interface{} {
+	if usage == nil {
+		return []interface{}{}
+	}
+
+	plans := make([]interface{}, 0, len(usage.Details))
+	for _, detail := range usage.Details {
+		if detail == nil {
+			continue
+		}
+
+		plans = append(plans, map[string]interface{}{
+			"plan":  detail.Plan,
+			"hours": detail.Hours,
+		})
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"start": usage.Start,
+			"end":   usage.End,
+			"cost":  usage.Cost,
+			"plans": plans,
+		},
+	}
+}
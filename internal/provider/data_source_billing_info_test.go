@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+)
+
+func Test_flattenBillingPriceDimensions(t *testing.T) {
+	r := require.New(t)
+
+	result := flattenBillingPriceDimensions([]*models.HashicorpCloudConsulamaAmaBillingSettingsPricesPriceDimension{
+		{
+			Name:    "servicesDimension",
+			Minimum: 25,
+			Tiers: []*models.HashicorpCloudConsulamaAmaBillingSettingsPricesPriceDimensionTier{
+				{Label: "1-50", UnitPrice: 0.027},
+				nil,
+				{Label: "51+", UnitPrice: 0.02},
+			},
+		},
+		nil,
+	})
+
+	r.Equal([]interface{}{
+		map[string]interface{}{
+			"name":    "servicesDimension",
+			"minimum": 25,
+			"tiers": []interface{}{
+				map[string]interface{}{"label": "1-50", "unit_price": 0.027},
+				map[string]interface{}{"label": "51+", "unit_price": 0.02},
+			},
+		},
+	}, result)
+}
+
+func Test_flattenBilledUsage(t *testing.T) {
+	tcs := map[string]struct {
+		input    *models.HashicorpCloudConsulamaAmaBilledUsage
+		expected []interface{}
+	}{
+		"nil usage": {
+			input:    nil,
+			expected: []interface{}{},
+		},
+		"usage across plans": {
+			input: &models.HashicorpCloudConsulamaAmaBilledUsage{
+				Start: "2021-06-01T00:00:00Z",
+				End:   "2021-06-02T00:00:00Z",
+				Cost:  12.5,
+				Details: []*models.HashicorpCloudConsulamaAmaBilledUsagePlanUsage{
+					{Plan: "on-demand", Hours: 10},
+					{Plan: "annual", Hours: 14},
+				},
+			},
+			expected: []interface{}{
+				map[string]interface{}{
+					"start": "2021-06-01T00:00:00Z",
+					"end":   "2021-06-02T00:00:00Z",
+					"cost":  12.5,
+					"plans": []interface{}{
+						map[string]interface{}{"plan": "on-demand", "hours": float64(10)},
+						map[string]interface{}{"plan": "annual", "hours": float64(14)},
+					},
+				},
+			},
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(tc.expected, flattenBilledUsage(tc.input))
+		})
+	}
+}
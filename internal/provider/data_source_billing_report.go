@@ -0,0 +1,352 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+// defaultBillingReportTimeoutDuration is the default timeout for reading a billing report.
+var defaultBillingReportTimeoutDuration = time.Minute * 5
+
+// billedDimensionElem is the schema of the units billed for a billing dimension.
+var billedDimensionElem = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"name": {
+			Description: "The name of the billing dimension.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"tiers": {
+			Description: "The units consumed in each tier of the billing dimension.",
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"label": {
+						Description: "The description of the tier.",
+						Type:        schema.TypeString,
+						Computed:    true,
+					},
+					"units": {
+						Description: "The number of units consumed in the tier.",
+						Type:        schema.TypeInt,
+						Computed:    true,
+					},
+				},
+			},
+		},
+	},
+}
+
+// dataSourceBillingReport is the data source for the monthly billing report of an HCS cluster.
+func dataSourceBillingReport() *schema.Resource {
+	return &schema.Resource{
+		Description: "The billing report data source provides the hourly billed items of an HCS cluster for a given month.",
+		ReadContext: dataSourceBillingReportRead,
+		Timeouts: &schema.ResourceTimeout{
+			Default: &defaultBillingReportTimeoutDuration,
+		},
+		Schema: map[string]*schema.Schema{
+			// Required inputs
+			"resource_group_name": {
+				Description:      "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateResourceGroupName,
+			},
+			"managed_application_name": {
+				Description:      "The name of the HCS Azure Managed Application.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateSlugID,
+			},
+			"month": {
+				Description:      "The month of the billing report in the format YYYY/MM, e.g. '2021/06'.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateBillingMonth,
+			},
+			// Computed outputs
+			"items": {
+				Description: "The hourly billed items of the month.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"plan": {
+							Description: "The name of the billing plan applied to the item.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"start": {
+							Description: "The start time of the item.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"end": {
+							Description: "The end time of the item.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"cost": {
+							Description: "The total cost billed for the item.",
+							Type:        schema.TypeFloat,
+							Computed:    true,
+						},
+						"dimensions": {
+							Description: "The units billed for each billing dimension.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        billedDimensionElem,
+						},
+					},
+				},
+			},
+			"total_cost": {
+				Description: "The total cost billed for the month.",
+				Type:        schema.TypeFloat,
+				Computed:    true,
+			},
+			"plan_totals": {
+				Description: "The cost and number of billed items of the month aggregated per billing plan.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"plan": {
+							Description: "The name of the billing plan.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"cost": {
+							Description: "The total cost billed for the plan.",
+							Type:        schema.TypeFloat,
+							Computed:    true,
+						},
+						"billed_items": {
+							Description: "The number of hourly items billed for the plan.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"dimension_totals": {
+				Description: "The units of the month aggregated per billing dimension and tier.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        billedDimensionElem,
+			},
+		},
+	}
+}
+
+// dataSourceBillingReportRead retrieves the billing report of the HCS cluster for the given month.
+func dataSourceBillingReportRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceGroupName := d.Get("resource_group_name").(string)
+	managedAppName := d.Get("managed_application_name").(string)
+	month := d.Get("month").(string)
+
+	managedAppClient := meta.(*clients.Client).ManagedApplication
+	app, err := managedAppClient.Get(ctx, resourceGroupName, managedAppName)
+	if err != nil {
+		if helper.IsAutoRestResponseCodeNotFound(app.Response) {
+			return diag.Errorf("unable to fetch billing report; HCS cluster not found (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+				managedAppName,
+				resourceGroupName,
+				meta.(*clients.Client).CorrelationRequestID,
+			)
+		}
+
+		return diag.Errorf("unable to check for presence of an existing HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+			err,
+		)
+	}
+
+	resp, err := meta.(*clients.Client).CustomResourceProvider.GetBillingReport(ctx, *app.ManagedResourceGroupID, month)
+	if err != nil {
+		return helper.ErrorDiagnostics(err, "unable to fetch billing report (Month %q) (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			month,
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	if err := d.Set("items", flattenBilledItems(resp.Usage)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	totalCost, planTotals := aggregateBilledItemsByPlan(resp.Usage)
+
+	if err := d.Set("total_cost", totalCost); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("plan_totals", planTotals); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("dimension_totals", aggregateBilledItemsByDimension(resp.Usage)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(*app.ID + "/billing-report/" + strings.ReplaceAll(month, "/", "-"))
+
+	return nil
+}
+
+// flattenBilledItems converts the billed items of a billing report to their schema representation.
+func flattenBilledItems(items []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItem) []interface{} {
+	flattened := make([]interface{}, 0, len(items))
+
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+
+		dimensions := make([]interface{}, 0, len(item.Dimensions))
+		for _, dimension := range item.Dimensions {
+			if dimension == nil {
+				continue
+			}
+
+			tiers := make([]interface{}, 0, len(dimension.Tiers))
+			for _, tier := range dimension.Tiers {
+				if tier == nil {
+					continue
+				}
+
+				tiers = append(tiers, map[string]interface{}{
+					"label": tier.Label,
+					"units": int(tier.Units),
+				})
+			}
+
+			dimensions = append(dimensions, map[string]interface{}{
+				"name":  dimension.Name,
+				"tiers": tiers,
+			})
+		}
+
+		flattened = append(flattened, map[string]interface{}{
+			"plan":       item.Plan,
+			"start":      item.Start,
+			"end":        item.End,
+			"cost":       item.Cost,
+			"dimensions": dimensions,
+		})
+	}
+
+	return flattened
+}
+
+// aggregateBilledItemsByPlan returns the total cost of the billed items, as well as
+// the cost and number of billed items per plan ordered by plan name.
+func aggregateBilledItemsByPlan(items []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItem) (float64, []interface{}) {
+	var totalCost float64
+	costs := make(map[string]float64)
+	counts := make(map[string]int)
+
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+
+		totalCost += item.Cost
+		costs[item.Plan] += item.Cost
+		counts[item.Plan]++
+	}
+
+	plans := make([]string, 0, len(counts))
+	for plan := range counts {
+		plans = append(plans, plan)
+	}
+	sort.Strings(plans)
+
+	planTotals := make([]interface{}, 0, len(plans))
+	for _, plan := range plans {
+		planTotals = append(planTotals, map[string]interface{}{
+			"plan":         plan,
+			"cost":         costs[plan],
+			"billed_items": counts[plan],
+		})
+	}
+
+	return totalCost, planTotals
+}
+
+// aggregateBilledItemsByDimension returns the units of the billed items summed per
+// dimension and tier, ordered by dimension name and tier label.
+func aggregateBilledItemsByDimension(items []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItem) []interface{} {
+	units := make(map[string]map[string]int)
+
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+
+		for _, dimension := range item.Dimensions {
+			if dimension == nil {
+				continue
+			}
+
+			if _, ok := units[dimension.Name]; !ok {
+				units[dimension.Name] = make(map[string]int)
+			}
+
+			for _, tier := range dimension.Tiers {
+				if tier == nil {
+					continue
+				}
+
+				units[dimension.Name][tier.Label] += int(tier.Units)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(units))
+	for name := range units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dimensionTotals := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		labels := make([]string, 0, len(units[name]))
+		for label := range units[name] {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+
+		tiers := make([]interface{}, 0, len(labels))
+		for _, label := range labels {
+			tiers = append(tiers, map[string]interface{}{
+				"label": label,
+				"units": units[name][label],
+			})
+		}
+
+		dimensionTotals = append(dimensionTotals, map[string]interface{}{
+			"name":  name,
+			"tiers": tiers,
+		})
+	}
+
+	return dimensionTotals
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+)
+
+// testBilledItems returns billed items spanning two plans and two dimensions.
+func testBilledItems() []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItem {
+	return []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItem{
+		{
+			Plan:  "on-demand",
+			Start: "2021-06-01T00:00:00Z",
+			End:   "2021-06-01T01:00:00Z",
+			Cost:  1.5,
+			Dimensions: []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItemDimension{
+				{
+					Name: "servicesDimension",
+					Tiers: []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItemDimensionTier{
+						{Label: "1-50", Units: 50},
+						{Label: "51+", Units: 3},
+					},
+				},
+			},
+		},
+		nil,
+		{
+			Plan:  "annual",
+			Start: "2021-06-01T01:00:00Z",
+			End:   "2021-06-01T02:00:00Z",
+			Cost:  1,
+			Dimensions: []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItemDimension{
+				{
+					Name: "servicesDimension",
+					Tiers: []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItemDimensionTier{
+						{Label: "1-50", Units: 50},
+					},
+				},
+				{
+					Name: "clientsDimension",
+					Tiers: []*models.HashicorpCloudConsulamaAmaGetBillingReportResponseBilledItemDimensionTier{
+						{Label: "all", Units: 7},
+					},
+				},
+			},
+		},
+		{
+			Plan:  "on-demand",
+			Start: "2021-06-01T02:00:00Z",
+			End:   "2021-06-01T03:00:00Z",
+			Cost:  1.5,
+		},
+	}
+}
+
+func Test_flattenBilledItems(t *testing.T) {
+	r := require.New(t)
+
+	result := flattenBilledItems(testBilledItems())
+	r.Len(result, 3)
+
+	first := result[0].(map[string]interface{})
+	r.Equal("on-demand", first["plan"])
+	r.Equal("2021-06-01T00:00:00Z", first["start"])
+	r.Equal(1.5, first["cost"])
+	r.Equal([]interface{}{
+		map[string]interface{}{
+			"name": "servicesDimension",
+			"tiers": []interface{}{
+				map[string]interface{}{"label": "1-50", "units": 50},
+				map[string]interface{}{"label": "51+", "units": 3},
+			},
+		},
+	}, first["dimensions"])
+}
+
+func Test_aggregateBilledItemsByPlan(t *testing.T) {
+	r := require.New(t)
+
+	totalCost, planTotals := aggregateBilledItemsByPlan(testBilledItems())
+	r.Equal(4.0, totalCost)
+	r.Equal([]interface{}{
+		map[string]interface{}{"plan": "annual", "cost": 1.0, "billed_items": 1},
+		map[string]interface{}{"plan": "on-demand", "cost": 3.0, "billed_items": 2},
+	}, planTotals)
+}
+
+func Test_aggregateBilledItemsByDimension(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal([]interface{}{
+		map[string]interface{}{
+			"name": "clientsDimension",
+			"tiers": []interface{}{
+				map[string]interface{}{"label": "all", "units": 7},
+			},
+		},
+		map[string]interface{}{
+			"name": "servicesDimension",
+			"tiers": []interface{}{
+				map[string]interface{}{"label": "1-50", "units": 100},
+				map[string]interface{}{"label": "51+", "units": 3},
+			},
+		},
+	}, aggregateBilledItemsByDimension(testBilledItems()))
+}
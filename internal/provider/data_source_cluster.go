@@ -70,6 +70,11 @@ func dataSourceCluster() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"min_consul_version": {
+				Description: "The minimum Consul version of the cluster.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 			"consul_datacenter": {
 				Description: "The Consul data center name of the cluster.",
 				Type:        schema.TypeString,
@@ -79,6 +84,7 @@ func dataSourceCluster() *schema.Resource {
 				Description: "The token used to join a federation of Consul clusters. If the cluster is not part of a federation, this field will be empty.",
 				Type:        schema.TypeString,
 				Computed:    true,
+				Sensitive:   true,
 			},
 			"consul_external_endpoint": {
 				Description: "Denotes that the cluster has an external endpoint for the Consul UI.",
@@ -108,6 +114,21 @@ func dataSourceCluster() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"audit_logging_enabled": {
+				Description: "Whether Consul audit logging is enabled for the cluster.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"audit_log_storage_container_url": {
+				Description: "The url of the Azure blob storage container audit logs are written to if `audit_logging_enabled` is `true`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"managed_identity_name": {
+				Description: "The name of the managed identity used for writing audit logs if `audit_logging_enabled` is `true`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 			"vnet_id": {
 				Description: "The ID of the cluster's managed VNet.",
 				Type:        schema.TypeString,
@@ -164,15 +185,41 @@ func dataSourceCluster() *schema.Resource {
 				Computed:    true,
 			},
 			"consul_config_file": {
-				Description: "The cluster config encoded as a Base64 string.",
+				Description: "The cluster config encoded as a Base64 string. This contains the gossip encryption key.",
 				Type:        schema.TypeString,
 				Computed:    true,
+				Sensitive:   true,
 			},
 			"consul_ca_file": {
 				Description: "The cluster CA file encoded as a Base64 string.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"consul_retry_join": {
+				Description: "The addresses Consul client agents use to join the cluster, decoded from `consul_config_file`.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"consul_gossip_encryption_key": {
+				Description: "The gossip encryption key of the cluster, decoded from `consul_config_file`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"consul_ca_pem": {
+				Description: "The PEM encoded CA chain of the cluster, decoded from `consul_ca_file`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"consul_ca_certificate": {
+				Description: "The metadata of the first certificate in `consul_ca_pem`. Empty if the certificate cannot be parsed.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        consulCACertificateElem,
+			},
 			"consul_connect": {
 				Description: "Denotes that Consul connect is enabled.",
 				Type:        schema.TypeBool,
@@ -220,11 +267,10 @@ func dataSourceClusterRead(ctx context.Context, d *schema.ResourceData, meta int
 	// Fetch the cluster managed resource
 	cluster, err := meta.(*clients.Client).CustomResourceProvider.FetchConsulCluster(ctx, *managedApp.ManagedResourceGroupID, clusterName)
 	if err != nil {
-		return diag.Errorf("unable to fetch HCS cluster Managed Resource (Managed Application ID %q) (Cluster Name %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to fetch HCS cluster Managed Resource (Managed Application ID %q) (Cluster Name %q) (Correlation ID %q)",
 			*managedApp.ID,
 			clusterName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
@@ -4,32 +4,24 @@
 package provider
 
 import (
-	"regexp"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
 )
 
-func TestAccDataSourceScaffolding(t *testing.T) {
-	t.Skip("data source not yet implemented, remove this once you add your own code")
+func TestDataSourceCluster(t *testing.T) {
+	r := require.New(t)
 
-	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: providerFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: testAccDataSourceScaffolding,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestMatchResourceAttr(
-						"data.scaffolding_data_source.foo", "sample_attribute", regexp.MustCompile("^ba")),
-				),
-			},
-		},
-	})
-}
+	_, client := testFakeServer(t)
+	clusterState := testCreateCluster(t, client)
+
+	state := testReadDataSource(t, dataSourceCluster(), map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+	}, client)
 
-const testAccDataSourceScaffolding = `
-data "scaffolding_data_source" "foo" {
-  sample_attribute = "bar"
+	for _, attr := range []string{"cluster_name", "consul_datacenter", "consul_cluster_id", "vnet_id", "consul_private_endpoint_url", "managed_application_id", "tags.env", "consul_retry_join.0", "consul_gossip_encryption_key", "consul_ca_pem", "consul_ca_certificate.0.sha256_fingerprint"} {
+		r.Equal(clusterState.Attributes[attr], state.Attributes[attr], attr)
+	}
+	r.Equal("v1.9.4", state.Attributes["consul_version"])
 }
-`
@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/consul"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+// defaultClusterUpgradeVersionsTimeoutDuration is the default timeout for reading the upgrade versions of a cluster.
+var defaultClusterUpgradeVersionsTimeoutDuration = time.Minute * 5
+
+// dataSourceClusterUpgradeVersions is the data source for the Consul versions an HCS cluster can be upgraded to.
+func dataSourceClusterUpgradeVersions() *schema.Resource {
+	return &schema.Resource{
+		Description: "The cluster upgrade versions data source provides the Consul versions an existing HCS cluster can be upgraded to.",
+		ReadContext: dataSourceClusterUpgradeVersionsRead,
+		Timeouts: &schema.ResourceTimeout{
+			Default: &defaultClusterUpgradeVersionsTimeoutDuration,
+		},
+		Schema: map[string]*schema.Schema{
+			// Required inputs
+			"resource_group_name": {
+				Description:      "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateResourceGroupName,
+			},
+			"managed_application_name": {
+				Description:      "The name of the HCS Azure Managed Application.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateSlugID,
+			},
+			// Optional inputs
+			"cluster_name": {
+				Description:      "The name of the cluster Managed Resource. If not specified, it is defaulted to the value of `managed_application_name`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateSlugID,
+			},
+			// Computed outputs
+			"current_version": {
+				Description: "The current Consul version of the cluster.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"versions": {
+				Description: "The Consul versions the cluster can be upgraded to, sorted from oldest to newest.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        consulVersionElem,
+			},
+			"recommended_version": {
+				Description: "The recommended Consul version to upgrade the cluster to. This is the version recommended by HCS if the cluster can be upgraded to it, and the newest version that is not a preview version otherwise. Empty if the cluster is on the latest version.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"is_latest": {
+				Description: "Whether the cluster is on the latest Consul version, i.e. it can only be upgraded to preview versions, if any.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// dataSourceClusterUpgradeVersionsRead lists the Consul versions the HCS cluster can be upgraded to.
+func dataSourceClusterUpgradeVersionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceGroupName := d.Get("resource_group_name").(string)
+	managedAppName := d.Get("managed_application_name").(string)
+
+	managedAppClient := meta.(*clients.Client).ManagedApplication
+	app, err := managedAppClient.Get(ctx, resourceGroupName, managedAppName)
+	if err != nil {
+		if helper.IsAutoRestResponseCodeNotFound(app.Response) {
+			return diag.Errorf("unable to list upgrade versions; HCS cluster not found (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+				managedAppName,
+				resourceGroupName,
+				meta.(*clients.Client).CorrelationRequestID,
+			)
+		}
+
+		return diag.Errorf("unable to check for presence of an existing HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+			err,
+		)
+	}
+
+	clusterName := managedAppName
+	v, ok := d.GetOk("cluster_name")
+	if ok {
+		clusterName = v.(string)
+	}
+
+	crpClient := meta.(*clients.Client).CustomResourceProvider
+	cluster, err := crpClient.FetchConsulCluster(ctx, *app.ManagedResourceGroupID, clusterName)
+	if err != nil {
+		return helper.ErrorDiagnostics(err, "unable to fetch HCS cluster Managed Resource (Managed Application %q) (Cluster Name %q) (Correlation ID %q)",
+			managedAppName,
+			clusterName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	resp, err := crpClient.ListUpgradeVersions(ctx, *app.ManagedResourceGroupID)
+	if err != nil {
+		return helper.ErrorDiagnostics(err, "unable to list upgrade versions for HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	upgradeVersions := consul.FromAMAVersions(resp.Versions)
+	consul.SortVersions(upgradeVersions)
+
+	var currentVersion string
+	if cluster.Properties != nil {
+		currentVersion = cluster.Properties.ConsulCurrentVersion
+	}
+
+	if err := d.Set("current_version", currentVersion); err != nil {
+		return diag.FromErr(err)
+	}
+
+	versions := make([]map[string]interface{}, 0, len(upgradeVersions))
+	for _, v := range upgradeVersions {
+		versions = append(versions, map[string]interface{}{
+			"version": v.Version,
+			"status":  v.Status,
+		})
+	}
+
+	if err := d.Set("versions", versions); err != nil {
+		return diag.FromErr(err)
+	}
+
+	latest, hasUpgrade := consul.LatestVersion(upgradeVersions)
+	recommendedVersion := latest.Version
+	for _, v := range upgradeVersions {
+		if v.Status == consul.StatusRecommended {
+			recommendedVersion = v.Version
+		}
+	}
+
+	if err := d.Set("recommended_version", recommendedVersion); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("is_latest", !hasUpgrade); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(*app.ID + "/upgrade-versions")
+
+	return nil
+}
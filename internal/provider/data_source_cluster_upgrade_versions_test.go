@@ -0,0 +1,46 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+)
+
+func TestDataSourceClusterUpgradeVersions(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	clusterState := testCreateCluster(t, client)
+
+	config := map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+	}
+
+	state := testReadDataSource(t, dataSourceClusterUpgradeVersions(), config, client)
+	r.Equal("v1.9.4", state.Attributes["current_version"])
+	r.Equal("2", state.Attributes["versions.#"])
+	r.Equal("v1.9.5", state.Attributes["versions.0.version"])
+	r.Equal("AVAILABLE", state.Attributes["versions.0.status"])
+	r.Equal("v1.10.0", state.Attributes["versions.1.version"])
+	r.Equal("PREVIEW", state.Attributes["versions.1.status"])
+	r.Equal("v1.9.5", state.Attributes["recommended_version"])
+	r.Equal("false", state.Attributes["is_latest"])
+
+	// Only preview versions are newer than the latest version
+	r.True(server.UpdateCluster(clusterState.ID, func(properties *models.HashicorpCloudConsulamaAmaClusterProperties) {
+		properties.ConsulCurrentVersion = "v1.9.5"
+	}))
+
+	state = testReadDataSource(t, dataSourceClusterUpgradeVersions(), config, client)
+	r.Equal("v1.9.5", state.Attributes["current_version"])
+	r.Equal("1", state.Attributes["versions.#"])
+	r.Equal("v1.10.0", state.Attributes["versions.0.version"])
+	r.Equal("", state.Attributes["recommended_version"])
+	r.Equal("true", state.Attributes["is_latest"])
+}
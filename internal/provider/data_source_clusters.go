@@ -0,0 +1,357 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-07-01/managedapplications"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+	"github.com/hashicorp/terraform-provider-hcs/internal/consul"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+// defaultClustersTimeoutDuration is the default timeout for listing HCS clusters.
+var defaultClustersTimeoutDuration = time.Minute * 10
+
+// clustersFilter contains the optional criteria used to narrow down the HCS clusters of a subscription.
+type clustersFilter struct {
+	// location, if set, must equal the Azure region of the cluster.
+	location string
+
+	// tags must all be present with the same values on the Managed Application.
+	tags map[string]string
+
+	// consulVersion, if set, must equal the current Consul version of the cluster.
+	consulVersion string
+
+	// state, if set, must equal the state of the cluster (case insensitive).
+	state string
+}
+
+// dataSourceClusters is the data source for all HCS clusters in the subscription.
+func dataSourceClusters() *schema.Resource {
+	return &schema.Resource{
+		Description: "The clusters data source lists the HCS clusters of the Azure subscription.",
+		ReadContext: dataSourceClustersRead,
+		Timeouts: &schema.ResourceTimeout{
+			Default: &defaultClustersTimeoutDuration,
+		},
+		Schema: map[string]*schema.Schema{
+			// Optional inputs
+			"resource_group_name": {
+				Description:      "The name of a Resource Group to limit the clusters to. If not specified, the clusters of all Resource Groups in the subscription are returned.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateResourceGroupName,
+			},
+			"location": {
+				Description: "The Azure region the clusters must be deployed to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"tags": {
+				Description: "A mapping of tags the HCS Azure Managed Applications must have.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"consul_version": {
+				Description:      "The Consul version the clusters must be running.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateSemVer,
+			},
+			"state": {
+				Description: "The state the clusters must be in, e.g. 'RUNNING'.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			// Computed outputs
+			"clusters": {
+				Description: "The HCS clusters matching the filters.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource_group_name": {
+							Description: "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"managed_application_name": {
+							Description: "The name of the HCS Azure Managed Application.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"managed_application_id": {
+							Description: "The ID of the HCS Azure Managed Application.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"managed_resource_group_name": {
+							Description: "The name of the Managed Resource Group in which the cluster resources belong.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"plan_name": {
+							Description: "The name of the Azure Marketplace HCS plan for the cluster.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"tags": {
+							Description: "A mapping of tags assigned to the HCS Azure Managed Application resource.",
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"cluster_name": {
+							Description: "The name of the cluster Managed Resource.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"cluster_mode": {
+							Description: "The mode of the cluster ('DEVELOPMENT' or 'PRODUCTION').",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"location": {
+							Description: "The Azure region that the cluster is deployed to.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"state": {
+							Description: "The state of the cluster.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"consul_version": {
+							Description: "The Consul version of the cluster.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"consul_datacenter": {
+							Description: "The Consul data center name of the cluster.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"consul_cluster_id": {
+							Description: "The cluster ID.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"consul_private_endpoint_url": {
+							Description: "The private URL for the Consul UI.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"consul_external_endpoint_url": {
+							Description: "The public URL for the Consul UI. This will be empty if the cluster has no external endpoint.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceClustersRead lists the HCS Managed Applications of the subscription and
+// the clusters of each of them.
+func dataSourceClustersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*clients.Client)
+
+	resourceGroupName := d.Get("resource_group_name").(string)
+
+	var apps managedapplications.ApplicationListResultIterator
+	var err error
+	if resourceGroupName != "" {
+		apps, err = client.ManagedApplication.ListByResourceGroupComplete(ctx, resourceGroupName)
+	} else {
+		apps, err = client.ManagedApplication.ListBySubscriptionComplete(ctx)
+	}
+	if err != nil {
+		return diag.Errorf("unable to list Managed Applications (Resource Group %q) (Correlation ID %q): %v",
+			resourceGroupName,
+			client.CorrelationRequestID,
+			err,
+		)
+	}
+
+	filter := expandClustersFilter(d)
+
+	clusters := make([]interface{}, 0)
+	for ; apps.NotDone(); err = apps.NextWithContext(ctx) {
+		if err != nil {
+			return diag.Errorf("unable to list Managed Applications (Resource Group %q) (Correlation ID %q): %v",
+				resourceGroupName,
+				client.CorrelationRequestID,
+				err,
+			)
+		}
+
+		app := apps.Value()
+		if !isHCSManagedApp(app, client.Config.MarketPlaceProductName) || !managedAppMatchesFilter(app, filter) {
+			continue
+		}
+
+		if app.ApplicationProperties == nil || app.ProvisioningState != managedapplications.ProvisioningStateSucceeded {
+			log.Printf("[WARN] skipping HCS cluster that is not provisioned (Managed Application ID %q)", *app.ID)
+			continue
+		}
+
+		resp, err := client.CustomResourceProvider.ListConsulClusters(ctx, *app.ManagedResourceGroupID)
+		if err != nil {
+			return helper.ErrorDiagnostics(err, "unable to list HCS clusters (Managed Application ID %q) (Correlation ID %q)",
+				*app.ID,
+				client.CorrelationRequestID,
+			)
+		}
+
+		for _, cluster := range resp.Value {
+			if cluster == nil || cluster.Properties == nil || !clusterMatchesFilter(cluster.Properties, filter) {
+				continue
+			}
+
+			flattened, err := flattenClusterSummary(app, cluster)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			clusters = append(clusters, flattened)
+		}
+	}
+
+	sortClusterSummaries(clusters)
+
+	if err := d.Set("clusters", clusters); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("/subscriptions/" + client.Account.SubscriptionId + "/hcs-clusters")
+
+	return nil
+}
+
+// expandClustersFilter builds a clustersFilter from the optional inputs of the clusters data source.
+func expandClustersFilter(d *schema.ResourceData) clustersFilter {
+	filter := clustersFilter{
+		location:      d.Get("location").(string),
+		consulVersion: d.Get("consul_version").(string),
+		state:         d.Get("state").(string),
+		tags:          make(map[string]string),
+	}
+
+	for k, v := range d.Get("tags").(map[string]interface{}) {
+		filter.tags[k] = v.(string)
+	}
+
+	return filter
+}
+
+// isHCSManagedApp determines if a Managed Application was purchased from the HCS offer on the Azure Marketplace.
+func isHCSManagedApp(app managedapplications.Application, productName string) bool {
+	if app.Plan == nil || app.Plan.Publisher == nil || app.Plan.Product == nil {
+		return false
+	}
+
+	return *app.Plan.Publisher == hcsMarketplacePublisher && *app.Plan.Product == productName
+}
+
+// managedAppMatchesFilter determines if a Managed Application matches the location and tags of the filter.
+func managedAppMatchesFilter(app managedapplications.Application, filter clustersFilter) bool {
+	if filter.location != "" && (app.Location == nil || normalizeLocation(*app.Location) != normalizeLocation(filter.location)) {
+		return false
+	}
+
+	for k, v := range filter.tags {
+		tag, ok := app.Tags[k]
+		if !ok || tag == nil || *tag != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clusterMatchesFilter determines if the properties of a cluster match the Consul version and state of the filter.
+func clusterMatchesFilter(properties *models.HashicorpCloudConsulamaAmaClusterProperties, filter clustersFilter) bool {
+	if filter.consulVersion != "" && !consul.EqualVersions(filter.consulVersion, properties.ConsulCurrentVersion) {
+		return false
+	}
+
+	if filter.state != "" && !strings.EqualFold(filter.state, string(properties.State)) {
+		return false
+	}
+
+	return true
+}
+
+// normalizeLocation converts an Azure region display name to its short name, e.g. 'West US 2' to 'westus2'.
+func normalizeLocation(location string) string {
+	return strings.ReplaceAll(strings.ToLower(location), " ", "")
+}
+
+// flattenClusterSummary converts a Managed Application and one of its clusters to the
+// schema representation used by the clusters data source.
+func flattenClusterSummary(app managedapplications.Application, cluster *models.HashicorpCloudConsulamaAmaClusterResponse) (map[string]interface{}, error) {
+	resourceGroupName, err := helper.ParseResourceGroupNameFromID(*app.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	managedResourceGroupName, err := helper.ParseResourceGroupNameFromID(*app.ManagedResourceGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var planName string
+	if app.Plan != nil && app.Plan.Name != nil {
+		planName = *app.Plan.Name
+	}
+
+	return map[string]interface{}{
+		"resource_group_name":          resourceGroupName,
+		"managed_application_name":     *app.Name,
+		"managed_application_id":       *app.ID,
+		"managed_resource_group_name":  managedResourceGroupName,
+		"plan_name":                    planName,
+		"tags":                         helper.FlattenTags(app.Tags),
+		"cluster_name":                 cluster.Name,
+		"cluster_mode":                 clusterModeFromProperties(cluster.Properties),
+		"location":                     cluster.Properties.Location,
+		"state":                        string(cluster.Properties.State),
+		"consul_version":               cluster.Properties.ConsulCurrentVersion,
+		"consul_datacenter":            cluster.Properties.ConsulDatacenter,
+		"consul_cluster_id":            cluster.Properties.ConsulClusterID,
+		"consul_private_endpoint_url":  cluster.Properties.ConsulPrivateEndpointURL,
+		"consul_external_endpoint_url": cluster.Properties.ConsulExternalEndpointURL,
+	}, nil
+}
+
+// sortClusterSummaries orders flattened clusters by Managed Application ID and cluster name,
+// so that the output does not depend on the order in which Azure lists the applications.
+func sortClusterSummaries(clusters []interface{}) {
+	sort.SliceStable(clusters, func(i, j int) bool {
+		a, b := clusters[i].(map[string]interface{}), clusters[j].(map[string]interface{})
+
+		if a["managed_application_id"] != b["managed_application_id"] {
+			return a["managed_application_id"].(string) < b["managed_application_id"].(string)
+		}
+
+		return a["cluster_name"].(string) < b["cluster_name"].(string)
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-07-01/managedapplications"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+func Test_isHCSManagedApp(t *testing.T) {
+	tcs := map[string]struct {
+		plan     *managedapplications.Plan
+		expected bool
+	}{
+		"no plan": {
+			plan:     nil,
+			expected: false,
+		},
+		"hcs plan": {
+			plan: &managedapplications.Plan{
+				Publisher: helper.String(hcsMarketplacePublisher),
+				Product:   helper.String("hcs-production"),
+			},
+			expected: true,
+		},
+		"other product": {
+			plan: &managedapplications.Plan{
+				Publisher: helper.String(hcsMarketplacePublisher),
+				Product:   helper.String("another-product"),
+			},
+			expected: false,
+		},
+		"other publisher": {
+			plan: &managedapplications.Plan{
+				Publisher: helper.String("another-publisher"),
+				Product:   helper.String("hcs-production"),
+			},
+			expected: false,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(tc.expected, isHCSManagedApp(managedapplications.Application{Plan: tc.plan}, "hcs-production"))
+		})
+	}
+}
+
+func Test_managedAppMatchesFilter(t *testing.T) {
+	app := managedapplications.Application{
+		Location: helper.String("westus2"),
+		Tags: map[string]*string{
+			"env":  helper.String("prod"),
+			"team": helper.String("platform"),
+		},
+	}
+
+	tcs := map[string]struct {
+		filter   clustersFilter
+		expected bool
+	}{
+		"empty filter": {
+			filter:   clustersFilter{},
+			expected: true,
+		},
+		"matching location display name": {
+			filter:   clustersFilter{location: "West US 2"},
+			expected: true,
+		},
+		"other location": {
+			filter:   clustersFilter{location: "eastus"},
+			expected: false,
+		},
+		"matching tags": {
+			filter:   clustersFilter{tags: map[string]string{"env": "prod"}},
+			expected: true,
+		},
+		"tag with other value": {
+			filter:   clustersFilter{tags: map[string]string{"env": "dev"}},
+			expected: false,
+		},
+		"missing tag": {
+			filter:   clustersFilter{tags: map[string]string{"env": "prod", "owner": "me"}},
+			expected: false,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(tc.expected, managedAppMatchesFilter(app, tc.filter))
+		})
+	}
+}
+
+func Test_clusterMatchesFilter(t *testing.T) {
+	properties := &models.HashicorpCloudConsulamaAmaClusterProperties{
+		ConsulCurrentVersion: "v1.9.4",
+		State:                models.HashicorpCloudConsulamaAmaClusterStateRUNNING,
+	}
+
+	tcs := map[string]struct {
+		filter   clustersFilter
+		expected bool
+	}{
+		"empty filter": {
+			filter:   clustersFilter{},
+			expected: true,
+		},
+		"matching version without prefix": {
+			filter:   clustersFilter{consulVersion: "1.9.4"},
+			expected: true,
+		},
+		"other version": {
+			filter:   clustersFilter{consulVersion: "1.8.0"},
+			expected: false,
+		},
+		"matching state in lower case": {
+			filter:   clustersFilter{state: "running"},
+			expected: true,
+		},
+		"other state": {
+			filter:   clustersFilter{state: "FAILED"},
+			expected: false,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(tc.expected, clusterMatchesFilter(properties, tc.filter))
+		})
+	}
+}
+
+func Test_sortClusterSummaries(t *testing.T) {
+	r := require.New(t)
+
+	clusters := []interface{}{
+		map[string]interface{}{"managed_application_id": "b", "cluster_name": "a"},
+		map[string]interface{}{"managed_application_id": "a", "cluster_name": "b"},
+		map[string]interface{}{"managed_application_id": "a", "cluster_name": "a"},
+	}
+
+	sortClusterSummaries(clusters)
+
+	r.Equal([]interface{}{
+		map[string]interface{}{"managed_application_id": "a", "cluster_name": "a"},
+		map[string]interface{}{"managed_application_id": "a", "cluster_name": "b"},
+		map[string]interface{}{"managed_application_id": "b", "cluster_name": "a"},
+	}, clusters)
+}
@@ -18,6 +18,22 @@ import (
 // defaultConsulVersionsTimeoutDuration is the default timeout for reading Consul versions.
 var defaultConsulVersionsTimeoutDuration = time.Minute * 5
 
+// consulVersionElem is the schema of a single Consul version returned by the Consul versions data source.
+var consulVersionElem = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"version": {
+			Description: "The Consul version.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"status": {
+			Description: "The status of the Consul version on HCS: `RECOMMENDED`, `AVAILABLE` or `PREVIEW`.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	},
+}
+
 // dataSourceConsulVersions is the data source for the Consul versions supported by HCS.
 func dataSourceConsulVersions() *schema.Resource {
 	return &schema.Resource{
@@ -27,6 +43,13 @@ func dataSourceConsulVersions() *schema.Resource {
 			Default: &defaultConsulVersionsTimeoutDuration,
 		},
 		Schema: map[string]*schema.Schema{
+			// Optional inputs
+			"constraint": {
+				Description:      "A version constraint, e.g. `~> 1.9` or `>= 1.8, < 1.10`, that the `versions` and `latest_matching` outputs are filtered by.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateVersionConstraint,
+			},
 			// Computed outputs
 			"recommended": {
 				Description: "The recommended Consul version for HCS clusters.",
@@ -49,6 +72,17 @@ func dataSourceConsulVersions() *schema.Resource {
 				},
 				Computed: true,
 			},
+			"versions": {
+				Description: "The Consul versions available on HCS that match the `constraint`, sorted from oldest to newest.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        consulVersionElem,
+			},
+			"latest_matching": {
+				Description: "The newest Consul version that matches the `constraint` and is not a preview version. Empty if no version matches.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -67,12 +101,12 @@ func dataSourceConsulVersionsRead(ctx context.Context, d *schema.ResourceData, m
 
 	for _, v := range versions {
 		switch v.Status {
-		case "RECOMMENDED":
+		case consul.StatusRecommended:
 			recommendedVersion = v.Version
 			availableVersions = append(availableVersions, v.Version)
-		case "AVAILABLE":
+		case consul.StatusAvailable:
 			availableVersions = append(availableVersions, v.Version)
-		case "PREVIEW":
+		case consul.StatusPreview:
 			previewVersions = append(previewVersions, v.Version)
 		}
 	}
@@ -92,7 +126,32 @@ func dataSourceConsulVersionsRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	d.SetId(fmt.Sprintf("recommended/%s/available_len/%d/preview_len/%d", recommendedVersion, len(availableVersions), len(previewVersions)))
+	constraint := d.Get("constraint").(string)
+	matchingVersions, err := consul.MatchingVersions(constraint, versions)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	matching := make([]map[string]interface{}, 0, len(matchingVersions))
+	for _, v := range matchingVersions {
+		matching = append(matching, map[string]interface{}{
+			"version": v.Version,
+			"status":  v.Status,
+		})
+	}
+
+	err = d.Set("versions", matching)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	latestMatching, _ := consul.LatestVersion(matchingVersions)
+	err = d.Set("latest_matching", latestMatching.Version)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("recommended/%s/available_len/%d/preview_len/%d/constraint/%s", recommendedVersion, len(availableVersions), len(previewVersions), constraint))
 
 	return nil
 }
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSourceConsulVersions(t *testing.T) {
+	tcs := map[string]struct {
+		constraint             string
+		expectedVersions       []string
+		expectedLatestMatching string
+	}{
+		"no constraint": {
+			expectedVersions:       []string{"v1.8.10", "v1.9.4", "v1.9.5", "v1.10.0"},
+			expectedLatestMatching: "v1.9.5",
+		},
+		"pinned minor version": {
+			constraint:             "~> 1.9.0",
+			expectedVersions:       []string{"v1.9.4", "v1.9.5"},
+			expectedLatestMatching: "v1.9.5",
+		},
+		"no match": {
+			constraint:             ">= 2.0",
+			expectedVersions:       []string{},
+			expectedLatestMatching: "",
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			_, client := testFakeServer(t)
+
+			config := map[string]interface{}{}
+			if tc.constraint != "" {
+				config["constraint"] = tc.constraint
+			}
+
+			state := testReadDataSource(t, dataSourceConsulVersions(), config, client)
+			r.Equal("v1.9.4", state.Attributes["recommended"])
+			r.Equal(tc.expectedLatestMatching, state.Attributes["latest_matching"])
+
+			versions := make([]string, 0)
+			for i := 0; i < len(tc.expectedVersions); i++ {
+				versions = append(versions, state.Attributes[fmt.Sprintf("versions.%d.version", i)])
+			}
+			r.Equal(tc.expectedVersions, versions)
+			r.Equal(fmt.Sprint(len(tc.expectedVersions)), state.Attributes["versions.#"])
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+// defaultFederationTimeoutDuration is the default timeout for reading a federation.
+var defaultFederationTimeoutDuration = time.Minute * 5
+
+// federatedClusterElem is the schema of a single datacenter returned by the federation data source.
+var federatedClusterElem = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"id": {
+			Description: "The ID of the cluster.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"name": {
+			Description: "The name of the HCS Azure Managed Application of the cluster.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"resource_group_name": {
+			Description: "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"subscription_id": {
+			Description: "The ID of the Azure subscription of the cluster.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	},
+}
+
+// dataSourceFederation is the data source for the federation of an HCS cluster.
+func dataSourceFederation() *schema.Resource {
+	return &schema.Resource{
+		Description: "The federation data source provides the primary and secondary datacenters and the state of the federation an HCS cluster is part of.",
+		ReadContext: dataSourceFederationRead,
+		Timeouts: &schema.ResourceTimeout{
+			Default: &defaultFederationTimeoutDuration,
+		},
+		Schema: map[string]*schema.Schema{
+			// Required inputs
+			"resource_group_name": {
+				Description:      "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateResourceGroupName,
+			},
+			"managed_application_name": {
+				Description:      "The name of the HCS Azure Managed Application. This can be the primary or any secondary datacenter of the federation.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateSlugID,
+			},
+			// Computed outputs
+			"federated": {
+				Description: "Whether the cluster is part of a federation. All other outputs are empty if it is not.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"state": {
+				Description: "The state of the federation: `RUNNING`, `PRIMARY_DATACENTER_MISSING` or `UNKNOWN`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"primary_datacenter": {
+				Description: "The primary datacenter of the federation. Empty if the state is `PRIMARY_DATACENTER_MISSING`.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        federatedClusterElem,
+			},
+			"secondary_datacenters": {
+				Description: "The secondary datacenters of the federation.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        federatedClusterElem,
+			},
+		},
+	}
+}
+
+// dataSourceFederationRead gets the federation the HCS cluster is part of.
+func dataSourceFederationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	managedAppName := d.Get("managed_application_name").(string)
+	resourceGroupName := d.Get("resource_group_name").(string)
+
+	managedApp, err := meta.(*clients.Client).ManagedApplication.Get(ctx, resourceGroupName, managedAppName)
+	if err != nil {
+		if helper.IsAutoRestResponseCodeNotFound(managedApp.Response) {
+			return diag.Errorf("unable to fetch federation; HCS cluster not found (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+				managedAppName,
+				resourceGroupName,
+				meta.(*clients.Client).CorrelationRequestID,
+			)
+		}
+
+		return diag.Errorf("unable to check for presence of an existing HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+			err,
+		)
+	}
+
+	crpClient := meta.(*clients.Client).CustomResourceProvider
+	federationResponse, err := crpClient.GetFederation(ctx, *managedApp.ManagedResourceGroupID, resourceGroupName)
+	federated := true
+	if err != nil {
+		// A not found error denotes that the cluster is not part of a federation
+		if !crpClient.IsCRPErrorAzureNotFound(err) {
+			return helper.ErrorDiagnostics(err, "unable to fetch federation of HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+				managedAppName,
+				resourceGroupName,
+				meta.(*clients.Client).CorrelationRequestID,
+			)
+		}
+
+		federated = false
+		federationResponse = models.HashicorpCloudConsulamaAmaGetFederationResponse{}
+	}
+
+	if err := d.Set("federated", federated); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("state", string(federationResponse.State)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	primaryDatacenter := make([]map[string]interface{}, 0, 1)
+	if federationResponse.PrimaryDatacenter != nil {
+		primaryDatacenter = append(primaryDatacenter, flattenFederatedCluster(federationResponse.PrimaryDatacenter))
+	}
+
+	if err := d.Set("primary_datacenter", primaryDatacenter); err != nil {
+		return diag.FromErr(err)
+	}
+
+	secondaryDatacenters := make([]map[string]interface{}, 0, len(federationResponse.SecondaryDatacenters))
+	for _, secondary := range federationResponse.SecondaryDatacenters {
+		if secondary == nil {
+			continue
+		}
+
+		secondaryDatacenters = append(secondaryDatacenters, flattenFederatedCluster(secondary))
+	}
+
+	if err := d.Set("secondary_datacenters", secondaryDatacenters); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(*managedApp.ID + "/federation")
+
+	return nil
+}
+
+// flattenFederatedCluster converts a datacenter of a federation to its representation in the state.
+func flattenFederatedCluster(cluster *models.HashicorpCloudConsulamaAmaFederatedClusterResponse) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                  cluster.ID,
+		"name":                cluster.Name,
+		"resource_group_name": cluster.ResourceGroup,
+		"subscription_id":     cluster.SubscriptionID,
+	}
+}
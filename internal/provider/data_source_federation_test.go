@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSourceFederation(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	primaryState := testCreateCluster(t, client)
+
+	config := map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+	}
+
+	// A cluster without secondaries is not part of a federation
+	state := testReadDataSource(t, dataSourceFederation(), config, client)
+	r.Equal("false", state.Attributes["federated"])
+	r.Equal("", state.Attributes["state"])
+	r.Equal("0", state.Attributes["primary_datacenter.#"])
+	r.Equal("0", state.Attributes["secondary_datacenters.#"])
+
+	tokenState := testReadDataSource(t, dataSourceFederationToken(), config, client)
+
+	secondaryConfig := map[string]interface{}{}
+	for k, v := range testClusterConfig {
+		secondaryConfig[k] = v
+	}
+	secondaryConfig["managed_application_name"] = "hcs-secondary"
+	secondaryConfig["consul_federation_token"] = tokenState.Attributes["token"]
+	secondaryState := testApplyResource(t, resourceCluster(), nil, secondaryConfig, client)
+
+	// The federation can be read from the primary and the secondaries
+	for _, managedAppName := range []string{"hcs-test", "hcs-secondary"} {
+		config["managed_application_name"] = managedAppName
+
+		state = testReadDataSource(t, dataSourceFederation(), config, client)
+		r.Equal("true", state.Attributes["federated"])
+		r.Equal("RUNNING", state.Attributes["state"])
+		r.Equal("1", state.Attributes["primary_datacenter.#"])
+		r.Equal(primaryState.Attributes["consul_cluster_id"], state.Attributes["primary_datacenter.0.id"])
+		r.Equal("hcs-test", state.Attributes["primary_datacenter.0.name"])
+		r.Equal(testResourceGroupName, state.Attributes["primary_datacenter.0.resource_group_name"])
+		r.Equal(server.SubscriptionID, state.Attributes["primary_datacenter.0.subscription_id"])
+		r.Equal("1", state.Attributes["secondary_datacenters.#"])
+		r.Equal(secondaryState.Attributes["consul_cluster_id"], state.Attributes["secondary_datacenters.0.id"])
+		r.Equal("hcs-secondary", state.Attributes["secondary_datacenters.0.name"])
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
 )
 
 // defaultFederationTokenTimeoutDuration is the default timeout for reading a federation token.
@@ -70,11 +71,10 @@ func dataSourceFederationTokenRead(ctx context.Context, d *schema.ResourceData,
 
 	federationTokenResponse, err := meta.(*clients.Client).CustomResourceProvider.CreateFederationToken(ctx, *managedApp.ManagedResourceGroupID, resourceGroupName)
 	if err != nil {
-		return diag.Errorf("unable to fetch a federation token for primary cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to fetch a federation token for primary cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
 			managedAppName,
 			resourceGroupName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
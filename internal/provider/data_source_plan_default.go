@@ -12,7 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
-	"github.com/hashicorp/terraform-provider-hcs/internal/hcsmeta"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
 )
 
 // defaultPlanDefaultsTimeoutDuration is the default timeout for reading plan defaults.
@@ -56,9 +56,10 @@ func dataSourcePlanDefaults() *schema.Resource {
 // dataSourcePlanDefaultsRead retrieves the HCS Meta plan defaults and sets the HCS plan defaults for
 // the Azure marketplace.
 func dataSourcePlanDefaultsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	planDefaults, err := hcsmeta.GetPlanDefaults(ctx)
-	if err != nil {
-		return diag.Errorf("unable to retrieve HCS Meta plan defaults: %v", err)
+	planDefaults, err := meta.(*clients.Client).Meta.GetPlanDefaults(ctx)
+	diags := helper.MetaDiagnostics(err, "unable to retrieve HCS Meta plan defaults")
+	if diags.HasError() {
+		return diags
 	}
 
 	if err := d.Set("plan_name", planDefaults.Name); err != nil {
@@ -69,7 +70,7 @@ func dataSourcePlanDefaultsRead(ctx context.Context, d *schema.ResourceData, met
 		return diag.FromErr(err)
 	}
 
-	if err := d.Set("publisher", "hashicorp-4665790"); err != nil {
+	if err := d.Set("publisher", hcsMarketplacePublisher); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -80,5 +81,5 @@ func dataSourcePlanDefaultsRead(ctx context.Context, d *schema.ResourceData, met
 
 	d.SetId(fmt.Sprintf("plan_version/%s/plan_name/%s/ama_api_version/%s", planDefaults.Version, planDefaults.Name, planDefaults.ManagedAppApiVersion))
 
-	return nil
+	return diags
 }
@@ -0,0 +1,328 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+// defaultSnapshotsTimeoutDuration is the default timeout for listing snapshots.
+var defaultSnapshotsTimeoutDuration = time.Minute * 5
+
+// snapshotElem is the schema of a single snapshot returned by the snapshots data source.
+var snapshotElem = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"id": {
+			Description: "The ID of the snapshot.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"name": {
+			Description: "The name of the snapshot.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"type": {
+			Description: "The type of the snapshot ('MANUAL' or 'AUTOMATIC').",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"state": {
+			Description: "The state of the snapshot.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"size": {
+			Description: "The size of the snapshot in bytes.",
+			Type:        schema.TypeInt,
+			Computed:    true,
+		},
+		"product_version": {
+			Description: "The Consul version of the cluster when the snapshot was taken.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"requested_at": {
+			Description: "Timestamp of when the snapshot was requested.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"finished_at": {
+			Description: "Timestamp of when the snapshot was finished.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"restored_at": {
+			Description: "Timestamp of when the snapshot was restored. If the snapshot has not been restored, this field will be blank.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	},
+}
+
+// snapshotFilter contains the optional criteria used to narrow down the snapshots of a cluster.
+type snapshotFilter struct {
+	// nameRegex, if set, must match the name of the snapshot.
+	nameRegex *regexp.Regexp
+
+	// snapshotType, if set, must equal the type of the snapshot (case insensitive).
+	snapshotType string
+
+	// requestedAfter, if set, must be before the time the snapshot was requested.
+	requestedAfter *time.Time
+
+	// requestedBefore, if set, must be after the time the snapshot was requested.
+	requestedBefore *time.Time
+}
+
+// dataSourceSnapshots is the data source for the snapshots of an HCS cluster.
+func dataSourceSnapshots() *schema.Resource {
+	return &schema.Resource{
+		Description: "The snapshots data source lists the manual and automatic Consul snapshots of an HCS cluster." +
+			" Snapshots currently have a retention policy of 30 days.",
+		ReadContext: dataSourceSnapshotsRead,
+		Timeouts: &schema.ResourceTimeout{
+			Default: &defaultSnapshotsTimeoutDuration,
+		},
+		Schema: map[string]*schema.Schema{
+			// Required inputs
+			"resource_group_name": {
+				Description:      "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateResourceGroupName,
+			},
+			"managed_application_name": {
+				Description:      "The name of the HCS Azure Managed Application.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateSlugID,
+			},
+			// Optional inputs
+			"name_regex": {
+				Description:      "A regular expression the snapshot name must match.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateRegexp,
+			},
+			"type": {
+				Description: "The type of snapshots to return ('MANUAL' or 'AUTOMATIC'). If not specified, snapshots of both types are returned.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ValidateDiagFunc: validateStringInSlice([]string{
+					"MANUAL",
+					"AUTOMATIC",
+				}, true),
+			},
+			"requested_after": {
+				Description:      "Only return snapshots requested after this RFC 3339 timestamp.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateRFC3339,
+			},
+			"requested_before": {
+				Description:      "Only return snapshots requested before this RFC 3339 timestamp.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateRFC3339,
+			},
+			// Computed outputs
+			"snapshots": {
+				Description: "The snapshots matching the filters, ordered from the most to the least recently requested.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        snapshotElem,
+			},
+			"latest": {
+				Description: "The most recently requested snapshot matching the filters. Empty if no snapshot matches.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        snapshotElem,
+			},
+		},
+	}
+}
+
+// dataSourceSnapshotsRead lists the snapshots of the HCS cluster and filters them
+// by the optional inputs.
+func dataSourceSnapshotsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceGroupName := d.Get("resource_group_name").(string)
+	managedAppName := d.Get("managed_application_name").(string)
+
+	managedAppClient := meta.(*clients.Client).ManagedApplication
+	app, err := managedAppClient.Get(ctx, resourceGroupName, managedAppName)
+	if err != nil {
+		if helper.IsAutoRestResponseCodeNotFound(app.Response) {
+			return diag.Errorf("unable to list snapshots; HCS cluster not found (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+				managedAppName,
+				resourceGroupName,
+				meta.(*clients.Client).CorrelationRequestID,
+			)
+		}
+
+		return diag.Errorf("unable to check for presence of an existing HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+			err,
+		)
+	}
+
+	filter, diagnostics := expandSnapshotFilter(d)
+	if diagnostics != nil {
+		return diagnostics
+	}
+
+	crpClient := meta.(*clients.Client).CustomResourceProvider
+	resp, err := crpClient.ListSnapshots(ctx, *app.ManagedResourceGroupID, resourceGroupName)
+	if err != nil {
+		return helper.ErrorDiagnostics(err, "unable to list snapshots (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	snapshots, err := flattenSnapshots(filterSnapshots(resp.Snapshots, filter))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("snapshots", snapshots); err != nil {
+		return diag.FromErr(err)
+	}
+
+	latest := make([]interface{}, 0, 1)
+	if len(snapshots) > 0 {
+		latest = append(latest, snapshots[0])
+	}
+	if err := d.Set("latest", latest); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(*app.ID + "/snapshots")
+
+	return nil
+}
+
+// expandSnapshotFilter builds a snapshotFilter from the optional inputs of the snapshots data source.
+func expandSnapshotFilter(d *schema.ResourceData) (snapshotFilter, diag.Diagnostics) {
+	var filter snapshotFilter
+
+	if v, ok := d.GetOk("name_regex"); ok {
+		re, err := regexp.Compile(v.(string))
+		if err != nil {
+			return filter, diag.Errorf("unable to compile name_regex: %v", err)
+		}
+		filter.nameRegex = re
+	}
+
+	if v, ok := d.GetOk("type"); ok {
+		filter.snapshotType = v.(string)
+	}
+
+	if v, ok := d.GetOk("requested_after"); ok {
+		t, err := time.Parse(time.RFC3339, v.(string))
+		if err != nil {
+			return filter, diag.Errorf("unable to parse requested_after: %v", err)
+		}
+		filter.requestedAfter = &t
+	}
+
+	if v, ok := d.GetOk("requested_before"); ok {
+		t, err := time.Parse(time.RFC3339, v.(string))
+		if err != nil {
+			return filter, diag.Errorf("unable to parse requested_before: %v", err)
+		}
+		filter.requestedBefore = &t
+	}
+
+	return filter, nil
+}
+
+// filterSnapshots returns the snapshots matching the filter, ordered from the
+// most to the least recently requested.
+func filterSnapshots(snapshots []*models.HashicorpCloudConsulamaAmaSnapshotProperties, filter snapshotFilter) []*models.HashicorpCloudConsulamaAmaSnapshotProperties {
+	filtered := make([]*models.HashicorpCloudConsulamaAmaSnapshotProperties, 0, len(snapshots))
+
+	for _, s := range snapshots {
+		if s == nil {
+			continue
+		}
+
+		if filter.nameRegex != nil && !filter.nameRegex.MatchString(s.Name) {
+			continue
+		}
+
+		if filter.snapshotType != "" && !strings.EqualFold(filter.snapshotType, s.Type) {
+			continue
+		}
+
+		requestedAt := time.Time(s.RequestedAt)
+		if filter.requestedAfter != nil && !requestedAt.After(*filter.requestedAfter) {
+			continue
+		}
+
+		if filter.requestedBefore != nil && !requestedAt.Before(*filter.requestedBefore) {
+			continue
+		}
+
+		filtered = append(filtered, s)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return time.Time(filtered[i].RequestedAt).After(time.Time(filtered[j].RequestedAt))
+	})
+
+	return filtered
+}
+
+// flattenSnapshots converts a slice of snapshots to the snapshot schema representation.
+func flattenSnapshots(snapshots []*models.HashicorpCloudConsulamaAmaSnapshotProperties) ([]interface{}, error) {
+	flattened := make([]interface{}, 0, len(snapshots))
+
+	for _, s := range snapshots {
+		// The size is not populated until the snapshot has finished.
+		var size int
+		if s.Size != "" {
+			var err error
+			size, err = strconv.Atoi(s.Size)
+			if err != nil {
+				return nil, fmt.Errorf("unable to convert snapshot size to int: %v", err)
+			}
+		}
+
+		var restoredAt string
+		if s.RestoredAt.String() != defaultRestoredAt {
+			restoredAt = s.RestoredAt.String()
+		}
+
+		flattened = append(flattened, map[string]interface{}{
+			"id":              s.ID,
+			"name":            s.Name,
+			"type":            s.Type,
+			"state":           s.State,
+			"size":            size,
+			"product_version": s.ProductVersion,
+			"requested_at":    s.RequestedAt.String(),
+			"finished_at":     s.FinishedAt.String(),
+			"restored_at":     restoredAt,
+		})
+	}
+
+	return flattened, nil
+}
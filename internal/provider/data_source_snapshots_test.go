@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients/hcs-ama-api-spec/models"
+)
+
+func Test_filterSnapshots(t *testing.T) {
+	day := func(d int) strfmt.DateTime {
+		return strfmt.DateTime(time.Date(2021, time.June, d, 0, 0, 0, 0, time.UTC))
+	}
+	after := time.Date(2021, time.June, 2, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2021, time.June, 4, 0, 0, 0, 0, time.UTC)
+
+	snapshots := []*models.HashicorpCloudConsulamaAmaSnapshotProperties{
+		{ID: "1", Name: "nightly-1", Type: "AUTOMATIC", RequestedAt: day(1)},
+		{ID: "3", Name: "nightly-3", Type: "AUTOMATIC", RequestedAt: day(3)},
+		nil,
+		{ID: "2", Name: "pre-upgrade", Type: "MANUAL", RequestedAt: day(2)},
+		{ID: "4", Name: "nightly-4", Type: "AUTOMATIC", RequestedAt: day(4)},
+	}
+
+	tcs := map[string]struct {
+		filter   snapshotFilter
+		expected []string
+	}{
+		"no filter sorts newest first": {
+			filter:   snapshotFilter{},
+			expected: []string{"4", "3", "2", "1"},
+		},
+		"name regex": {
+			filter:   snapshotFilter{nameRegex: regexp.MustCompile("^nightly-")},
+			expected: []string{"4", "3", "1"},
+		},
+		"type is case insensitive": {
+			filter:   snapshotFilter{snapshotType: "manual"},
+			expected: []string{"2"},
+		},
+		"requested after is exclusive": {
+			filter:   snapshotFilter{requestedAfter: &after},
+			expected: []string{"4", "3"},
+		},
+		"requested between": {
+			filter:   snapshotFilter{requestedAfter: &after, requestedBefore: &before},
+			expected: []string{"3"},
+		},
+		"no match": {
+			filter:   snapshotFilter{nameRegex: regexp.MustCompile("^weekly-")},
+			expected: []string{},
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			ids := make([]string, 0)
+			for _, s := range filterSnapshots(snapshots, tc.filter) {
+				ids = append(ids, s.ID)
+			}
+			r.Equal(tc.expected, ids)
+		})
+	}
+}
+
+func Test_flattenSnapshots(t *testing.T) {
+	r := require.New(t)
+
+	requestedAt, err := strfmt.ParseDateTime("2021-06-01T00:00:00.000Z")
+	r.NoError(err)
+	restoredAt, err := strfmt.ParseDateTime(defaultRestoredAt)
+	r.NoError(err)
+
+	result, err := flattenSnapshots([]*models.HashicorpCloudConsulamaAmaSnapshotProperties{
+		{
+			ID:             "snapshot-id",
+			Name:           "nightly",
+			Type:           "AUTOMATIC",
+			State:          "COMPLETED",
+			Size:           "1024",
+			ProductVersion: "v1.9.5",
+			RequestedAt:    requestedAt,
+			FinishedAt:     requestedAt,
+			RestoredAt:     restoredAt,
+		},
+		{
+			ID:    "pending-id",
+			State: "PENDING",
+		},
+	})
+	r.NoError(err)
+	r.Len(result, 2)
+
+	snapshot := result[0].(map[string]interface{})
+	r.Equal(1024, snapshot["size"])
+	r.Equal("v1.9.5", snapshot["product_version"])
+	r.Equal("2021-06-01T00:00:00.000Z", snapshot["requested_at"])
+	r.Equal("", snapshot["restored_at"])
+
+	pending := result[1].(map[string]interface{})
+	r.Equal(0, pending["size"])
+
+	_, err = flattenSnapshots([]*models.HashicorpCloudConsulamaAmaSnapshotProperties{{Size: "abc"}})
+	r.Error(err)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+// ephemeralResourceClusterRootToken represents the ephemeral cluster root token, which creates a root
+// token for the cluster's ACL system that is never stored in the plan or state.
+type ephemeralResourceClusterRootToken struct {
+	client *clients.Client
+}
+
+var _ ephemeral.EphemeralResourceWithConfigure = &ephemeralResourceClusterRootToken{}
+
+// ephemeralResourceClusterRootTokenModel is the data of the ephemeral cluster root token.
+type ephemeralResourceClusterRootTokenModel struct {
+	ResourceGroupName      types.String `tfsdk:"resource_group_name"`
+	ManagedApplicationName types.String `tfsdk:"managed_application_name"`
+	AccessorID             types.String `tfsdk:"accessor_id"`
+	SecretID               types.String `tfsdk:"secret_id"`
+	KubernetesSecret       types.String `tfsdk:"kubernetes_secret"`
+}
+
+func newEphemeralResourceClusterRootToken() ephemeral.EphemeralResource {
+	return &ephemeralResourceClusterRootToken{}
+}
+
+func (r *ephemeralResourceClusterRootToken) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_root_token"
+}
+
+func (r *ephemeralResourceClusterRootToken) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The ephemeral cluster root token creates a root token to bootstrap the cluster's ACL system without storing it in the plan or state." +
+			" A new root token is created each time Terraform opens the ephemeral resource, which is during both plan and apply, and invalidates the previous root token of the cluster." +
+			" Requires Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			// Required inputs
+			"resource_group_name": schema.StringAttribute{
+				Description: "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+				Required:    true,
+				Validators: []validator.String{
+					sdkStringValidator{description: "value must be a valid resource group name", validate: validateResourceGroupName},
+				},
+			},
+			"managed_application_name": schema.StringAttribute{
+				Description: "The name of the HCS Azure Managed Application.",
+				Required:    true,
+				Validators: []validator.String{
+					sdkStringValidator{description: "value must be a valid slug ID", validate: validateSlugID},
+				},
+			},
+			// Computed outputs
+			"accessor_id": schema.StringAttribute{
+				Description: "The accessor ID of the root ACL token.",
+				Computed:    true,
+			},
+			"secret_id": schema.StringAttribute{
+				Description: "The secret ID of the root ACL token.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"kubernetes_secret": schema.StringAttribute{
+				Description: "The root ACL token Base64 encoded in a Kubernetes secret.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func (r *ephemeralResourceClusterRootToken) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	r.client = ephemeralResourceClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Open creates a new root token for the cluster, which invalidates its previous root token.
+func (r *ephemeralResourceClusterRootToken) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ephemeralResourceClusterRootTokenModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultClusterRootTokenTimeoutDuration)
+	defer cancel()
+
+	resourceGroupName := data.ResourceGroupName.ValueString()
+	managedAppName := data.ManagedApplicationName.ValueString()
+
+	app, err := r.client.ManagedApplication.Get(ctx, resourceGroupName, managedAppName)
+	if err != nil {
+		if helper.IsAutoRestResponseCodeNotFound(app.Response) {
+			resp.Diagnostics.AddError(fmt.Sprintf("unable to create root token; HCS cluster not found (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+				managedAppName,
+				resourceGroupName,
+				r.client.CorrelationRequestID,
+			), "")
+			return
+		}
+
+		resp.Diagnostics.Append(frameworkDiagnostics(helper.ErrorDiagnostics(err, "unable to check for presence of existing HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			managedAppName,
+			resourceGroupName,
+			r.client.CorrelationRequestID,
+		))...)
+		return
+	}
+
+	rootTokenResp, err := r.client.CustomResourceProvider.CreateRootToken(ctx, *app.ApplicationProperties.ManagedResourceGroupID)
+	if err != nil {
+		resp.Diagnostics.Append(frameworkDiagnostics(helper.ErrorDiagnostics(err, "unable to create HCS cluster root token (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			managedAppName,
+			resourceGroupName,
+			r.client.CorrelationRequestID,
+		))...)
+		return
+	}
+
+	// Terraform opens ephemeral resources during both plan and apply, so every plan rotates the
+	// root token. Surface this, since it breaks clients still using the previous root token.
+	resp.Diagnostics.AddWarning(
+		fmt.Sprintf("created a new root token for HCS cluster (Managed Application %q) (Resource Group %q)", managedAppName, resourceGroupName),
+		"The hcs_cluster_root_token ephemeral resource creates a new root token each time it is opened, including during plan. "+
+			"The previous root token of the cluster, including the consul_root_token_accessor_id and consul_root_token_secret_id "+
+			"of the hcs_cluster resource and any hcs_cluster_root_token resource, is no longer valid.",
+	)
+
+	data.AccessorID = types.StringValue(rootTokenResp.MasterToken.AccessorID)
+	data.SecretID = types.StringValue(rootTokenResp.MasterToken.SecretID)
+	data.KubernetesSecret = types.StringValue(generateKubernetesSecret(rootTokenResp.MasterToken.SecretID, managedAppName))
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEphemeralResourceClusterRootToken_open(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	clusterState := testCreateCluster(t, client)
+
+	config := map[string]string{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+	}
+
+	resp := testOpenEphemeralResource(t, newEphemeralResourceClusterRootToken(), config, client)
+	r.False(resp.Diagnostics.HasError(), "open failed: %+v", resp.Diagnostics)
+	r.Len(resp.Diagnostics.Warnings(), 1)
+	r.Contains(resp.Diagnostics.Warnings()[0].Detail(), "including during plan")
+
+	var accessorID, secretID, kubernetesSecret string
+	r.False(resp.Result.GetAttribute(context.Background(), path.Root("accessor_id"), &accessorID).HasError())
+	r.False(resp.Result.GetAttribute(context.Background(), path.Root("secret_id"), &secretID).HasError())
+	r.False(resp.Result.GetAttribute(context.Background(), path.Root("kubernetes_secret"), &kubernetesSecret).HasError())
+	r.Equal(server.RootTokenAccessorID(clusterState.ID), accessorID)
+	r.NotEmpty(secretID)
+	r.Equal(generateKubernetesSecret(secretID, "hcs-test"), kubernetesSecret)
+
+	// Each open creates a new root token, which invalidates the previous one
+	resp = testOpenEphemeralResource(t, newEphemeralResourceClusterRootToken(), config, client)
+	r.False(resp.Diagnostics.HasError(), "open failed: %+v", resp.Diagnostics)
+	r.Len(resp.Diagnostics.Warnings(), 1)
+	r.NotEqual(accessorID, server.RootTokenAccessorID(clusterState.ID))
+
+	// A missing cluster fails
+	config["managed_application_name"] = "hcs-missing"
+	resp = testOpenEphemeralResource(t, newEphemeralResourceClusterRootToken(), config, client)
+	r.True(resp.Diagnostics.HasError())
+	r.Contains(resp.Diagnostics[0].Summary(), "unable to create root token; HCS cluster not found")
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+// ephemeralResourceFederationToken represents the ephemeral federation token, which creates a
+// federation token for an HCS cluster that is never stored in the plan or state.
+type ephemeralResourceFederationToken struct {
+	client *clients.Client
+}
+
+var _ ephemeral.EphemeralResourceWithConfigure = &ephemeralResourceFederationToken{}
+
+// ephemeralResourceFederationTokenModel is the data of the ephemeral federation token.
+type ephemeralResourceFederationTokenModel struct {
+	ResourceGroupName      types.String `tfsdk:"resource_group_name"`
+	ManagedApplicationName types.String `tfsdk:"managed_application_name"`
+	Token                  types.String `tfsdk:"token"`
+}
+
+func newEphemeralResourceFederationToken() ephemeral.EphemeralResource {
+	return &ephemeralResourceFederationToken{}
+}
+
+func (r *ephemeralResourceFederationToken) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_federation_token"
+}
+
+func (r *ephemeralResourceFederationToken) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The ephemeral federation token creates a federation token for the primary cluster of a federation without storing it in the plan or state." +
+			" Pass it to `consul_federation_token_wo` of a secondary `hcs_cluster` to join the federation. Requires Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			// Required inputs
+			"resource_group_name": schema.StringAttribute{
+				Description: "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+				Required:    true,
+				Validators: []validator.String{
+					sdkStringValidator{description: "value must be a valid resource group name", validate: validateResourceGroupName},
+				},
+			},
+			"managed_application_name": schema.StringAttribute{
+				Description: "The name of the HCS Azure Managed Application.",
+				Required:    true,
+				Validators: []validator.String{
+					sdkStringValidator{description: "value must be a valid slug ID", validate: validateSlugID},
+				},
+			},
+			// Computed output
+			"token": schema.StringAttribute{
+				Description: "The federation token.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func (r *ephemeralResourceFederationToken) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	r.client = ephemeralResourceClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// Open creates a new federation token for the primary cluster. Federation tokens are not persisted
+// in HCS, so a new one is created each time the ephemeral resource is opened.
+func (r *ephemeralResourceFederationToken) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ephemeralResourceFederationTokenModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultFederationTokenTimeoutDuration)
+	defer cancel()
+
+	resourceGroupName := data.ResourceGroupName.ValueString()
+	managedAppName := data.ManagedApplicationName.ValueString()
+
+	managedApp, err := r.client.ManagedApplication.Get(ctx, resourceGroupName, managedAppName)
+	if err != nil {
+		resp.Diagnostics.Append(frameworkDiagnostics(helper.ErrorDiagnostics(err, "unable to fetch HCS cluster to be used as primary federation cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			managedAppName,
+			resourceGroupName,
+			r.client.CorrelationRequestID,
+		))...)
+		return
+	}
+
+	federationTokenResponse, err := r.client.CustomResourceProvider.CreateFederationToken(ctx, *managedApp.ManagedResourceGroupID, resourceGroupName)
+	if err != nil {
+		resp.Diagnostics.Append(frameworkDiagnostics(helper.ErrorDiagnostics(err, "unable to fetch a federation token for primary cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			managedAppName,
+			resourceGroupName,
+			r.client.CorrelationRequestID,
+		))...)
+		return
+	}
+
+	data.Token = types.StringValue(federationTokenResponse.FederationToken)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+func TestEphemeralResourceFederationToken_open(t *testing.T) {
+	r := require.New(t)
+
+	_, client := testFakeServer(t)
+	testCreateCluster(t, client)
+
+	tokenState := testReadDataSource(t, dataSourceFederationToken(), map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+	}, client)
+
+	resp := testOpenEphemeralResource(t, newEphemeralResourceFederationToken(), map[string]string{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+	}, client)
+	r.False(resp.Diagnostics.HasError(), "open failed: %+v", resp.Diagnostics)
+
+	var token string
+	r.False(resp.Result.GetAttribute(context.Background(), path.Root("token"), &token).HasError())
+	r.True(helper.FederationTokensHaveSamePrimary(tokenState.Attributes["token"], token))
+}
@@ -0,0 +1,190 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/version"
+)
+
+// NewMuxServer returns a factory for the provider server, which serves the resources and data sources
+// of the plugin SDK provider together with the ephemeral resources of the framework provider.
+func NewMuxServer(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	primary := New()()
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		primary.GRPCProvider,
+		providerserver.NewProtocol5(newFrameworkProvider(primary)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return muxServer.ProviderServer, nil
+}
+
+// frameworkProvider serves the ephemeral resources, which the plugin SDK does not support. It shares
+// the schema and the configured client of the plugin SDK provider it is muxed with.
+type frameworkProvider struct {
+	// primary is the plugin SDK provider. The mux configures it before the framework provider.
+	primary *schema.Provider
+}
+
+var _ fwprovider.ProviderWithEphemeralResources = &frameworkProvider{}
+
+// newFrameworkProvider returns the framework provider which is muxed with the passed plugin SDK provider.
+func newFrameworkProvider(primary *schema.Provider) fwprovider.Provider {
+	return &frameworkProvider{primary: primary}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ fwprovider.MetadataRequest, resp *fwprovider.MetadataResponse) {
+	resp.TypeName = "hcs"
+	resp.Version = version.ProviderVersion
+}
+
+// Schema mirrors the schema of the plugin SDK provider, since muxed providers must have identical schemas.
+// Required attributes with a DefaultFunc are optional, as the plugin SDK reports them to Terraform.
+func (p *frameworkProvider) Schema(_ context.Context, _ fwprovider.SchemaRequest, resp *fwprovider.SchemaResponse) {
+	attributes := make(map[string]fwschema.Attribute, len(p.primary.Schema))
+
+	for name, s := range p.primary.Schema {
+		required := s.Required && s.DefaultFunc == nil
+		description := schema.SchemaDescriptionBuilder(s)
+
+		switch s.Type {
+		case schema.TypeString:
+			attributes[name] = fwschema.StringAttribute{
+				Description:        description,
+				Required:           required,
+				Optional:           !required,
+				Sensitive:          s.Sensitive,
+				DeprecationMessage: s.Deprecated,
+			}
+		case schema.TypeInt:
+			attributes[name] = fwschema.Int64Attribute{
+				Description:        description,
+				Required:           required,
+				Optional:           !required,
+				Sensitive:          s.Sensitive,
+				DeprecationMessage: s.Deprecated,
+			}
+		case schema.TypeBool:
+			attributes[name] = fwschema.BoolAttribute{
+				Description:        description,
+				Required:           required,
+				Optional:           !required,
+				Sensitive:          s.Sensitive,
+				DeprecationMessage: s.Deprecated,
+			}
+		default:
+			resp.Diagnostics.AddError("Unsupported provider attribute type",
+				fmt.Sprintf("The type %s of the provider attribute %q cannot be mirrored by the framework provider.", s.Type, name))
+		}
+	}
+
+	resp.Schema = fwschema.Schema{Attributes: attributes}
+}
+
+// Configure passes the client of the plugin SDK provider to the ephemeral resources. The mux configures
+// the plugin SDK provider first and stops if that fails, so the client has been built by now.
+func (p *frameworkProvider) Configure(_ context.Context, _ fwprovider.ConfigureRequest, resp *fwprovider.ConfigureResponse) {
+	resp.EphemeralResourceData = p.primary.Meta()
+}
+
+func (p *frameworkProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		newEphemeralResourceClusterRootToken,
+		newEphemeralResourceFederationToken,
+	}
+}
+
+func (p *frameworkProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *frameworkProvider) Resources(_ context.Context) []func() resource.Resource {
+	return nil
+}
+
+// frameworkDiagnostics converts plugin SDK diagnostics to framework diagnostics.
+func frameworkDiagnostics(diags diag.Diagnostics) fwdiag.Diagnostics {
+	var result fwdiag.Diagnostics
+
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			result.AddError(d.Summary, d.Detail)
+		} else {
+			result.AddWarning(d.Summary, d.Detail)
+		}
+	}
+
+	return result
+}
+
+// sdkStringValidator adapts the validation func of a plugin SDK string attribute to the framework,
+// so that the ephemeral resources validate their arguments like the resources and data sources.
+type sdkStringValidator struct {
+	// description describes the valid values.
+	description string
+
+	// validate is the plugin SDK validation func.
+	validate schema.SchemaValidateDiagFunc
+}
+
+var _ validator.String = sdkStringValidator{}
+
+func (v sdkStringValidator) Description(_ context.Context) string {
+	return v.description
+}
+
+func (v sdkStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v sdkStringValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, d := range v.validate(req.ConfigValue.ValueString(), nil) {
+		if d.Severity == diag.Error {
+			resp.Diagnostics.AddAttributeError(req.Path, d.Summary, d.Detail)
+		} else {
+			resp.Diagnostics.AddAttributeWarning(req.Path, d.Summary, d.Detail)
+		}
+	}
+}
+
+// ephemeralResourceClient returns the client passed by the provider to the Configure method of an
+// ephemeral resource. It is nil if the provider has not been configured yet, e.g. during validation.
+func ephemeralResourceClient(providerData interface{}, diags *fwdiag.Diagnostics) *clients.Client {
+	if providerData == nil {
+		return nil
+	}
+
+	client, ok := providerData.(*clients.Client)
+	if !ok {
+		diags.AddError("Unexpected provider data",
+			fmt.Sprintf("Expected *clients.Client, got %T. Please report this issue to the provider developers.", providerData))
+		return nil
+	}
+
+	return client
+}
@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMuxServer(t *testing.T) {
+	r := require.New(t)
+
+	serverFactory, err := NewMuxServer(context.Background())
+	r.NoError(err)
+
+	// The mux fails to get the schema if the schemas of the muxed providers differ
+	resp, err := serverFactory().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	r.NoError(err)
+	for _, d := range resp.Diagnostics {
+		r.NotEqual(tfprotov5.DiagnosticSeverityError, d.Severity, "%s: %s", d.Summary, d.Detail)
+	}
+
+	r.Contains(resp.ResourceSchemas, "hcs_cluster")
+	r.Contains(resp.EphemeralResourceSchemas, "hcs_cluster_root_token")
+	r.Contains(resp.EphemeralResourceSchemas, "hcs_federation_token")
+}
+
+// testOpenEphemeralResource opens an ephemeral resource with the passed config, like terraform apply would.
+func testOpenEphemeralResource(t *testing.T, r ephemeral.EphemeralResource, config map[string]string, meta interface{}) *ephemeral.OpenResponse {
+	ctx := context.Background()
+
+	configureResp := &ephemeral.ConfigureResponse{}
+	r.(ephemeral.EphemeralResourceWithConfigure).Configure(ctx, ephemeral.ConfigureRequest{ProviderData: meta}, configureResp)
+	require.False(t, configureResp.Diagnostics.HasError(), "configure failed: %+v", configureResp.Diagnostics)
+
+	schemaResp := &ephemeral.SchemaResponse{}
+	r.Schema(ctx, ephemeral.SchemaRequest{}, schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError(), "schema failed: %+v", schemaResp.Diagnostics)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+	values := map[string]tftypes.Value{}
+	for name := range schemaResp.Schema.Attributes {
+		if value, ok := config[name]; ok {
+			values[name] = tftypes.NewValue(tftypes.String, value)
+		} else {
+			values[name] = tftypes.NewValue(tftypes.String, nil)
+		}
+	}
+
+	resp := &ephemeral.OpenResponse{
+		Result: tfsdk.EphemeralResultData{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(objectType, nil),
+		},
+	}
+	r.Open(ctx, ephemeral.OpenRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(objectType, values),
+		},
+	}, resp)
+
+	return resp
+}
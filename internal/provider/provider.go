@@ -7,12 +7,14 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-azure-helpers/authentication"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/hcsmeta"
 	"github.com/hashicorp/terraform-provider-hcs/version"
 )
 
@@ -36,17 +38,26 @@ func New() func() *schema.Provider {
 	return func() *schema.Provider {
 		p := &schema.Provider{
 			DataSourcesMap: map[string]*schema.Resource{
-				"hcs_agent_helm_config":       dataSourceAgentHelmConfig(),
-				"hcs_agent_kubernetes_secret": dataSourceAgentConfigKubernetesSecret(),
-				"hcs_cluster":                 dataSourceCluster(),
-				"hcs_consul_versions":         dataSourceConsulVersions(),
-				"hcs_federation_token":        dataSourceFederationToken(),
-				"hcs_plan_defaults":           dataSourcePlanDefaults(),
+				"hcs_agent_cloud_init":         dataSourceAgentCloudInit(),
+				"hcs_agent_config":             dataSourceAgentConfig(),
+				"hcs_agent_helm_config":        dataSourceAgentHelmConfig(),
+				"hcs_agent_kubernetes_secret":  dataSourceAgentConfigKubernetesSecret(),
+				"hcs_billing_info":             dataSourceBillingInfo(),
+				"hcs_billing_report":           dataSourceBillingReport(),
+				"hcs_cluster":                  dataSourceCluster(),
+				"hcs_cluster_upgrade_versions": dataSourceClusterUpgradeVersions(),
+				"hcs_clusters":                 dataSourceClusters(),
+				"hcs_consul_versions":          dataSourceConsulVersions(),
+				"hcs_federation":               dataSourceFederation(),
+				"hcs_federation_token":         dataSourceFederationToken(),
+				"hcs_plan_defaults":            dataSourcePlanDefaults(),
+				"hcs_snapshots":                dataSourceSnapshots(),
 			},
 			ResourcesMap: map[string]*schema.Resource{
 				"hcs_cluster":            resourceCluster(),
 				"hcs_cluster_root_token": resourceClusterRootToken(),
 				"hcs_snapshot":           resourceSnapshot(),
+				"hcs_snapshot_restore":   resourceSnapshotRestore(),
 			},
 			Schema: map[string]*schema.Schema{
 				"hcp_api_domain": {
@@ -61,6 +72,30 @@ func New() func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("HCP_MARKETPLACE_PRODUCT_NAME", "hcs-production"),
 					Description: "The HashiCorp Consul Service product name on the Azure marketplace.",
 				},
+				"hcs_meta_source": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("HCS_META_SOURCE", hcsmeta.DefaultSource),
+					Description: "The URL prefix or local directory of the HCS meta repository, which provides the supported regions and Azure Marketplace plan defaults. A snapshot embedded in the provider is used if it cannot be read.",
+				},
+				"operation_poll_interval": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("HCS_OPERATION_POLL_INTERVAL", 10),
+					Description: "The number of seconds to wait before polling an async HCS operation for the first time. The delay grows exponentially with each subsequent poll.",
+				},
+				"operation_poll_max_interval": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("HCS_OPERATION_POLL_MAX_INTERVAL", 60),
+					Description: "The maximum number of seconds to wait between two polls of an async HCS operation.",
+				},
+				"operation_poll_max_transient_failures": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("HCS_OPERATION_POLL_MAX_TRANSIENT_FAILURES", 3),
+					Description: "The number of consecutive transient errors, e.g. throttled or failed requests, that are tolerated while polling an async HCS operation.",
+				},
 				// We must support the same optional fields found in the azurerm provider schema
 				// that are used for authentication to Azure. They are prefixed with azure_ below.
 				"azure_subscription_id": {
@@ -168,6 +203,11 @@ func configure(p *schema.Provider) func(context.Context, *schema.ResourceData) (
 
 		userAgent := p.UserAgent("terraform-provider-hcs", version.ProviderVersion)
 
+		pollConfig, err := expandOperationPollConfig(d)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
 		clientOptions := clients.Options{
 			ProviderUserAgent: userAgent,
 			AzureAuthConfig:   authConfig,
@@ -175,6 +215,8 @@ func configure(p *schema.Provider) func(context.Context, *schema.ResourceData) (
 				HCPApiDomain:           d.Get("hcp_api_domain").(string),
 				MarketPlaceProductName: d.Get("hcs_marketplace_product_name").(string),
 				SourceChannel:          userAgent,
+				OperationPollConfig:    pollConfig,
+				HCSMetaSource:          d.Get("hcs_meta_source").(string),
 			},
 		}
 
@@ -186,3 +228,28 @@ func configure(p *schema.Provider) func(context.Context, *schema.ResourceData) (
 		return c, nil
 	}
 }
+
+// expandOperationPollConfig builds the config used to poll async HCS operations from the provider config.
+func expandOperationPollConfig(d *schema.ResourceData) (clients.OperationPollConfig, error) {
+	config := clients.DefaultOperationPollConfig
+
+	interval := d.Get("operation_poll_interval").(int)
+	maxInterval := d.Get("operation_poll_max_interval").(int)
+	maxTransientFailures := d.Get("operation_poll_max_transient_failures").(int)
+
+	if interval < 1 {
+		return config, fmt.Errorf("operation_poll_interval must be at least 1 second, got %d", interval)
+	}
+	if maxInterval < interval {
+		return config, fmt.Errorf("operation_poll_max_interval (%d) must not be less than operation_poll_interval (%d)", maxInterval, interval)
+	}
+	if maxTransientFailures < 0 {
+		return config, fmt.Errorf("operation_poll_max_transient_failures must not be negative, got %d", maxTransientFailures)
+	}
+
+	config.Interval = time.Duration(interval) * time.Second
+	config.MaxInterval = time.Duration(maxInterval) * time.Second
+	config.MaxTransientFailures = maxTransientFailures
+
+	return config, nil
+}
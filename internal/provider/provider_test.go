@@ -4,11 +4,27 @@
 package provider
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io/ioutil"
 	"testing"
+	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/fakehcs"
 )
 
+// testResourceGroupName is the name of the Resource Group created in the fake HCS server.
+const testResourceGroupName = "test-rg"
+
 // providerFactories are used to instantiate a provider during acceptance testing.
 // The factory function will be invoked for every Terraform CLI command executed
 // to create a provider server to which the CLI can reattach.
@@ -24,6 +40,183 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func Test_expandOperationPollConfig(t *testing.T) {
+	tcs := map[string]struct {
+		input       map[string]interface{}
+		interval    time.Duration
+		maxInterval time.Duration
+		maxFailures int
+		expectedErr string
+	}{
+		"custom settings": {
+			input: map[string]interface{}{
+				"operation_poll_interval":               5,
+				"operation_poll_max_interval":           30,
+				"operation_poll_max_transient_failures": 0,
+			},
+			interval:    5 * time.Second,
+			maxInterval: 30 * time.Second,
+			maxFailures: 0,
+		},
+		"interval too short": {
+			input: map[string]interface{}{
+				"operation_poll_interval":     0,
+				"operation_poll_max_interval": 30,
+			},
+			expectedErr: "operation_poll_interval must be at least 1 second, got 0",
+		},
+		"max interval less than interval": {
+			input: map[string]interface{}{
+				"operation_poll_interval":     30,
+				"operation_poll_max_interval": 10,
+			},
+			expectedErr: "operation_poll_max_interval (10) must not be less than operation_poll_interval (30)",
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			d := schema.TestResourceDataRaw(t, New()().Schema, tc.input)
+
+			config, err := expandOperationPollConfig(d)
+			if tc.expectedErr != "" {
+				r.EqualError(err, tc.expectedErr)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal(tc.interval, config.Interval)
+			r.Equal(tc.maxInterval, config.MaxInterval)
+			r.Equal(tc.maxFailures, config.MaxTransientFailures)
+		})
+	}
+}
+
+// testFakeServer starts a fake HCS server with a Resource Group named testResourceGroupName and
+// returns it together with a client which sends all requests to it.
+func testFakeServer(t *testing.T) (*fakehcs.Server, *clients.Client) {
+	server := fakehcs.NewServer()
+	t.Cleanup(server.Close)
+
+	client, err := clients.Build(context.Background(), server.ClientOptions())
+	require.NoError(t, err)
+
+	server.AddResourceGroup(testResourceGroupName, "westus2")
+
+	return server, client
+}
+
+// testPGPKey generates a PGP key and returns it together with its base64 encoded public key.
+func testPGPKey(t *testing.T) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", &packet.Config{RSABits: 1024})
+	require.NoError(t, err)
+
+	publicKey := new(bytes.Buffer)
+	require.NoError(t, entity.Serialize(publicKey))
+
+	return entity, base64.StdEncoding.EncodeToString(publicKey.Bytes())
+}
+
+// testPGPDecrypt decrypts a base64 encoded value encrypted with the passed PGP key.
+func testPGPDecrypt(t *testing.T, entity *openpgp.Entity, encrypted string) string {
+	ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+	require.NoError(t, err)
+
+	md, err := openpgp.ReadMessage(bytes.NewReader(ciphertext), openpgp.EntityList{entity}, nil, nil)
+	require.NoError(t, err)
+
+	plaintext, err := ioutil.ReadAll(md.UnverifiedBody)
+	require.NoError(t, err)
+
+	return string(plaintext)
+}
+
+// testApplyResource plans and applies the passed config for a resource, like terraform apply would.
+// A nil state creates the resource.
+func testApplyResource(t *testing.T, r *schema.Resource, state *terraform.InstanceState, config map[string]interface{}, meta interface{}) *terraform.InstanceState {
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	if diff == nil {
+		return state
+	}
+
+	newState, diags := r.Apply(context.Background(), state, diff, meta)
+	require.False(t, diags.HasError(), "apply failed: %+v", diags)
+	require.NotNil(t, newState)
+
+	return newState
+}
+
+// testResourceConfigWriteOnly returns the config of a resource with write-only attributes, which
+// Terraform only passes in the raw config.
+func testResourceConfigWriteOnly(r *schema.Resource, config map[string]interface{}, writeOnly map[string]string) *terraform.ResourceConfig {
+	attributes := map[string]cty.Value{}
+	for name, attributeType := range r.CoreConfigSchema().ImpliedType().AttributeTypes() {
+		attributes[name] = cty.NullVal(attributeType)
+	}
+	for name, value := range writeOnly {
+		attributes[name] = cty.StringVal(value)
+	}
+
+	resourceConfig := terraform.NewResourceConfigRaw(config)
+	resourceConfig.CtyValue = cty.ObjectVal(attributes)
+
+	return resourceConfig
+}
+
+// testApplyResourceWriteOnly is testApplyResource with write-only attributes.
+func testApplyResourceWriteOnly(t *testing.T, r *schema.Resource, state *terraform.InstanceState, config map[string]interface{}, writeOnly map[string]string, meta interface{}) *terraform.InstanceState {
+	resourceConfig := testResourceConfigWriteOnly(r, config, writeOnly)
+
+	diff, err := r.Diff(context.Background(), state, resourceConfig, meta)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	diff.RawConfig = resourceConfig.CtyValue
+
+	newState, diags := r.Apply(context.Background(), state, diff, meta)
+	require.False(t, diags.HasError(), "apply failed: %+v", diags)
+	require.NotNil(t, newState)
+
+	return newState
+}
+
+// testRefreshResource refreshes the state of a resource, like terraform refresh would.
+func testRefreshResource(t *testing.T, r *schema.Resource, state *terraform.InstanceState, meta interface{}) *terraform.InstanceState {
+	newState, diags := r.RefreshWithoutUpgrade(context.Background(), state, meta)
+	require.False(t, diags.HasError(), "refresh failed: %+v", diags)
+
+	return newState
+}
+
+// testImportResource imports a resource by ID and refreshes its state, like terraform import would.
+func testImportResource(t *testing.T, r *schema.Resource, id string, meta interface{}) *terraform.InstanceState {
+	imported, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: id}), meta)
+	require.NoError(t, err)
+	require.Len(t, imported, 1)
+
+	return testRefreshResource(t, r, imported[0].State(), meta)
+}
+
+// testDestroyResource destroys a resource, like terraform destroy would.
+func testDestroyResource(t *testing.T, r *schema.Resource, state *terraform.InstanceState, meta interface{}) {
+	newState, diags := r.Apply(context.Background(), state, &terraform.InstanceDiff{Destroy: true}, meta)
+	require.False(t, diags.HasError(), "destroy failed: %+v", diags)
+	require.Nil(t, newState)
+}
+
+// testReadDataSource reads a data source with the passed config.
+func testReadDataSource(t *testing.T, r *schema.Resource, config map[string]interface{}, meta interface{}) *terraform.InstanceState {
+	diff, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+
+	state, diags := r.ReadDataApply(context.Background(), diff, meta)
+	require.False(t, diags.HasError(), "read failed: %+v", diags)
+
+	return state
+}
+
 func testAccPreCheck(t *testing.T) {
 	// You can add code here to run prior to any test case execution, for example assertions
 	// about the appropriate environment variables being set are common to see in a pre-check
@@ -5,6 +5,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -12,7 +13,8 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-07-01/managedapplications"
-	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -31,12 +33,46 @@ var createUpdateTimeoutDuration = time.Minute * 60
 // before a cluster delete operation should timeout.
 var deleteTimeoutDuration = time.Minute * 25
 
+// hcsMarketplacePublisher is the publisher of the HCS offer on the Azure Marketplace.
+const hcsMarketplacePublisher = "hashicorp-4665790"
+
 // managedAppParamValue is the container struct for passing AMA values on creation/update.
 type managedAppParamValue struct {
 	// Value is the value of the AMA param
 	Value interface{} `json:"value"`
 }
 
+// consulCACertificateElem is the schema of the CA certificate metadata of a cluster.
+var consulCACertificateElem = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"subject": {
+			Description: "The distinguished name of the certificate subject.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"serial_number": {
+			Description: "The serial number of the certificate as colon separated hex bytes.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"sha256_fingerprint": {
+			Description: "The SHA-256 fingerprint of the certificate as colon separated hex bytes.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"not_before": {
+			Description: "The RFC 3339 timestamp from which the certificate is valid.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"not_after": {
+			Description: "The RFC 3339 timestamp after which the certificate is no longer valid.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	},
+}
+
 // resourceCluster represents an HCS Cluster resource.
 // Most of the CRUD involves the Azure Managed Application and Custom Resource Provider actions.
 func resourceCluster() *schema.Resource {
@@ -55,6 +91,7 @@ func resourceCluster() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceClusterImport,
 		},
+		CustomizeDiff: resourceClusterCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			// Required inputs
 			"resource_group_name": {
@@ -122,12 +159,15 @@ func resourceCluster() *schema.Resource {
 						return false
 					}
 
-					actualConsulVersion := version.Must(version.NewVersion(old))
-					currentTFVersion := version.Must(version.NewVersion(new))
 					log.Printf("[DEBUG] Actual Consul Version %v", old)
 					log.Printf("[DEBUG] Current TF Version %v", new)
-					// suppres diff if the specified min_consul_version is <= to the actual consul version
-					return currentTFVersion.LessThanOrEqual(actualConsulVersion)
+					// suppress diff if the specified min_consul_version is <= to the actual consul version
+					c, err := consul.CompareVersions(new, old)
+					if err != nil {
+						// Invalid versions are reported by validation, so do not suppress the diff
+						return false
+					}
+					return c <= 0
 				},
 			},
 			"consul_datacenter": {
@@ -143,13 +183,30 @@ func resourceCluster() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 				ForceNew:    true,
-				DiffSuppressFunc: func(_, old, new string, _ *schema.ResourceData) bool {
+				Sensitive:   true,
+				DiffSuppressFunc: func(_, old, new string, d *schema.ResourceData) bool {
+					// An imported cluster has the federation token in the state, which must not force recreation
+					// if the token it was created with is passed to consul_federation_token_wo instead.
+					if new == "" && rawConfigString(d, "consul_federation_token_wo") != "" {
+						return true
+					}
+
 					// Since federation tokens are not persisted in HCS, we generate a new one for each federation
 					// token data source read. We don't want to force recreation of the cluster if the 'Primary' claim
 					// of the 'new' JWT (federation token) matches the 'Primary' claim for the old token.
 					return helper.FederationTokensHaveSamePrimary(old, new)
 				},
 			},
+			"consul_federation_token_wo": {
+				Description: "The token used to join a federation of Consul clusters, e.g. from the `hcs_federation_token` ephemeral resource." +
+					" Unlike `consul_federation_token`, it is never stored in the plan or state. It is only used when the cluster is created, so changing it has no effect." +
+					" Requires Terraform 1.11 or later.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				WriteOnly:     true,
+				Sensitive:     true,
+				ConflictsWith: []string{"consul_federation_token"},
+			},
 			"consul_external_endpoint": {
 				Description: "Denotes that the cluster has an external endpoint for the Consul UI.",
 				Type:        schema.TypeBool,
@@ -204,6 +261,26 @@ func resourceCluster() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 			},
+			"federation_delete_wait": {
+				Description:      "The maximum amount of time to wait for the secondary clusters of a federation to be deleted before deleting this cluster if it is the primary, e.g. `30m`. This allows destroying all clusters of a federation in a single run. The wait is also bounded by the delete timeout. If not specified, deleting a primary cluster that still has secondary clusters fails immediately.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateDuration,
+			},
+			"pgp_key": {
+				Description:      "A base64 encoded or ASCII armored PGP public key used to encrypt the secret ID of the root ACL token generated upon cluster creation, or a Keybase reference of the form `keybase:<username>`, which is resolved from `keybase_public_keys`. If specified, `consul_root_token_encrypted_secret_id` is set instead of `consul_root_token_secret_id`. The key is only used when the cluster is created, so changing it afterwards has no effect; use the `hcs_cluster_root_token` resource to create a root token encrypted with another key.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validatePGPKey,
+			},
+			"keybase_public_keys": {
+				Description: "A map of Keybase usernames to their PGP public keys, as exported with `keybase pgp export -q <username>`. A `keybase:<username>` reference in `pgp_key` is resolved to the key of the username. The keys are not fetched from keybase.io.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"managed_identity_name": {
 				Description: "The name of the managed identity used for writing audit logs if `audit_logging_enable` is `true`.",
 				Type:        schema.TypeString,
@@ -255,6 +332,14 @@ func resourceCluster() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"planned_upgrade_path": {
+				Description: "The Consul versions the cluster is upgraded to, in order, to reach `min_consul_version`. HCS upgrades a cluster by at most one minor release at a time, so a cluster that is several minor releases behind is upgraded through the intermediate versions. This is computed during plan when `min_consul_version` changes and holds the path of the last upgrade afterwards. If an upgrade fails partway, it holds the versions the cluster has not been upgraded to yet.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"consul_automatic_upgrades": {
 				Description: "Denotes that automatic Consul upgrades are enabled.",
 				Type:        schema.TypeBool,
@@ -271,15 +356,41 @@ func resourceCluster() *schema.Resource {
 				Computed:    true,
 			},
 			"consul_config_file": {
-				Description: "The cluster config encoded as a Base64 string.",
+				Description: "The cluster config encoded as a Base64 string. This contains the gossip encryption key.",
 				Type:        schema.TypeString,
 				Computed:    true,
+				Sensitive:   true,
 			},
 			"consul_ca_file": {
 				Description: "The cluster CA file encoded as a Base64 string.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"consul_retry_join": {
+				Description: "The addresses Consul client agents use to join the cluster, decoded from `consul_config_file`.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"consul_gossip_encryption_key": {
+				Description: "The gossip encryption key of the cluster, decoded from `consul_config_file`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"consul_ca_pem": {
+				Description: "The PEM encoded CA chain of the cluster, decoded from `consul_ca_file`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"consul_ca_certificate": {
+				Description: "The metadata of the first certificate in `consul_ca_pem`. Empty if the certificate cannot be parsed.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        consulCACertificateElem,
+			},
 			"consul_connect": {
 				Description: "Denotes that Consul connect is enabled.",
 				Type:        schema.TypeBool,
@@ -301,24 +412,232 @@ func resourceCluster() *schema.Resource {
 				Computed:    true,
 			},
 			"consul_root_token_accessor_id": {
-				Description: "The accessor ID of the root ACL token that is generated upon cluster creation. If a new root token is generated using the `hcs_cluster_root_token` resource, this field is no longer valid.",
+				Description: "The accessor ID of the root ACL token that is generated upon cluster creation. If a new root token is generated using the `hcs_cluster_root_token` resource or ephemeral resource, this field is no longer valid. The ephemeral resource generates a new root token each time it is opened, including during plan.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
 			"consul_root_token_secret_id": {
-				Description: "The secret ID of the root ACL token that is generated upon cluster creation. If a new root token is generated using the `hcs_cluster_root_token` resource, this field is no longer valid.",
+				Description: "The secret ID of the root ACL token that is generated upon cluster creation. If a new root token is generated using the `hcs_cluster_root_token` resource or ephemeral resource, this field is no longer valid. The ephemeral resource generates a new root token each time it is opened, including during plan.",
 				Type:        schema.TypeString,
 				Computed:    true,
 				Sensitive:   true,
 			},
+			"consul_root_token_encrypted_secret_id": {
+				Description: "The secret ID of the root ACL token that is generated upon cluster creation, encrypted with `pgp_key` and base64 encoded. It can be decrypted with `base64 --decode | gpg --decrypt`. Empty if `pgp_key` is not specified.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"consul_root_token_key_fingerprint": {
+				Description: "The fingerprint of the PGP key used to encrypt `consul_root_token_encrypted_secret_id`. Empty if `pgp_key` is not specified.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 		},
 	}
 }
 
+// errUnavailableConsulVersions is the error when the Consul versions available on HCP cannot be fetched.
+var errUnavailableConsulVersions = errors.New("unable to fetch available HCP Consul versions")
+
+// attributeError returns an error in the planned value of a resource attribute, which Terraform
+// reports for the attribute in the configuration. err describes why the value is invalid.
+func attributeError(attribute string, err error) error {
+	return cty.GetAttrPath(attribute).NewError(fmt.Errorf("invalid value for %s: %v", attribute, err))
+}
+
+// resourceClusterCustomizeDiff validates the planned cluster during terraform plan, so that invalid
+// configurations fail before they are applied. It checks that the location is supported by HCS, that
+// the Consul version is available on creation and can be upgraded to on update, and that an audit
+// log storage container is configured if audit logging is enabled. On update, the upgrade path to
+// the Consul version is planned. Values that are unknown during plan, and Consul versions while the
+// HCP API is unreachable, are checked on apply instead.
+func resourceClusterCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	var errs *multierror.Error
+
+	if d.Id() == "" {
+		errs = multierror.Append(errs, validateClusterLocation(ctx, d, meta)...)
+		errs = multierror.Append(errs, validateClusterConsulVersion(ctx, d, meta)...)
+	} else if d.HasChange("min_consul_version") {
+		errs = multierror.Append(errs, planClusterUpgradePath(ctx, d, meta)...)
+	}
+
+	if err := validateKeybasePGPKey(d); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	if d.NewValueKnown("audit_logging_enabled") && d.NewValueKnown("audit_log_storage_container_url") &&
+		d.Get("audit_logging_enabled").(bool) && d.Get("audit_log_storage_container_url").(string) == "" {
+		errs = multierror.Append(errs, attributeError("audit_log_storage_container_url", fmt.Errorf("must be set when audit_logging_enabled is true")))
+	}
+
+	if errs == nil || len(errs.Errors) == 0 {
+		return nil
+	}
+
+	// Terraform only reports an error for its attribute if the error is returned as is, so the first
+	// attribute error is returned and the other errors are logged.
+	first := 0
+	for i, err := range errs.Errors {
+		var pathErr cty.PathError
+		if errors.As(err, &pathErr) {
+			first = i
+			break
+		}
+	}
+	for i, err := range errs.Errors {
+		if i != first {
+			log.Printf("[WARN] additional error planning HCS cluster: %v", err)
+		}
+	}
+
+	return errs.Errors[first]
+}
+
+// validateClusterLocation checks that the location of a new cluster, which defaults to the location
+// of its Resource Group, is supported by HCS.
+func validateClusterLocation(ctx context.Context, d *schema.ResourceDiff, meta interface{}) []error {
+	var location string
+	if v, ok := d.GetOk("location"); ok && d.NewValueKnown("location") {
+		location = normalizeLocation(v.(string))
+	} else if d.NewValueKnown("resource_group_name") {
+		// The Resource Group may be created by the same plan, in which case it is checked on apply.
+		resourceGroupName := d.Get("resource_group_name").(string)
+		resourceGroup, err := meta.(*clients.Client).ResourceGroup.Get(ctx, resourceGroupName)
+		if err != nil {
+			if !helper.IsAutoRestResponseCodeNotFound(resourceGroup.Response) {
+				log.Printf("[WARN] unable to fetch Resource Group %q (Correlation ID %q); location is checked on apply: %v",
+					resourceGroupName,
+					meta.(*clients.Client).CorrelationRequestID,
+					err,
+				)
+			}
+			return nil
+		}
+		if resourceGroup.Location == nil {
+			return nil
+		}
+		location = *resourceGroup.Location
+	}
+
+	if location == "" {
+		return nil
+	}
+
+	supportedRegions, err := meta.(*clients.Client).Meta.GetSupportedRegions(ctx)
+	var fallbackErr *hcsmeta.FallbackError
+	if err != nil && !errors.As(err, &fallbackErr) {
+		return []error{fmt.Errorf("unable to retrieve supported HCS regions: %v", err)}
+	}
+
+	if !hcsmeta.RegionIsSupported(location, supportedRegions) {
+		return []error{attributeError("location", fmt.Errorf("unsupported location: %s; expected location to be one of %+v", location, supportedRegions))}
+	}
+
+	return nil
+}
+
+// validateClusterConsulVersion checks that the min_consul_version of a new cluster is available on HCS.
+func validateClusterConsulVersion(ctx context.Context, d *schema.ResourceDiff, meta interface{}) []error {
+	v, ok := d.GetOk("min_consul_version")
+	if !ok || !d.NewValueKnown("min_consul_version") {
+		return nil
+	}
+
+	availableConsulVersions, err := consul.GetAvailableHCPConsulVersions(ctx, meta.(*clients.Client).Config.HCPApiDomain)
+	if err != nil || availableConsulVersions == nil {
+		// The version is checked again on apply, so an unreachable HCP API must not fail the plan.
+		log.Printf("[WARN] %v; min_consul_version is checked on apply: %v", errUnavailableConsulVersions, err)
+		return nil
+	}
+
+	if !consul.IsValidVersion(v.(string), availableConsulVersions) {
+		return []error{attributeError("min_consul_version", fmt.Errorf("specified Consul version (%s) is unavailable; must be one of: %+v", v.(string), availableConsulVersions))}
+	}
+
+	return nil
+}
+
+// planClusterUpgradePath sets the planned_upgrade_path of an existing cluster to the Consul versions
+// the cluster has to be upgraded to in order to reach the changed min_consul_version.
+func planClusterUpgradePath(ctx context.Context, d *schema.ResourceDiff, meta interface{}) []error {
+	v, ok := d.GetOk("min_consul_version")
+	if !ok || !d.NewValueKnown("min_consul_version") {
+		return []error{d.SetNewComputed("planned_upgrade_path")}
+	}
+
+	managedApp, err := meta.(*clients.Client).ManagedApplication.GetByID(ctx, d.Id())
+	if err != nil {
+		// A cluster that no longer exists is removed from the state on apply.
+		if helper.IsAutoRestResponseCodeNotFound(managedApp.Response) {
+			return []error{d.SetNewComputed("planned_upgrade_path")}
+		}
+
+		return []error{fmt.Errorf("unable to fetch HCS cluster (Managed Application ID %q) (Correlation ID %q): %v",
+			d.Id(),
+			meta.(*clients.Client).CorrelationRequestID,
+			err,
+		)}
+	}
+
+	upgradePath, err := clusterUpgradePath(ctx, meta, managedApp, d.Get("consul_version").(string), v.(string))
+	if errors.Is(err, errUnavailableConsulVersions) {
+		// The upgrade path is planned again on apply, so an unreachable HCP API must not fail the plan.
+		log.Printf("[WARN] %v; the upgrade path is planned on apply", err)
+		return []error{d.SetNewComputed("planned_upgrade_path")}
+	}
+	if err != nil {
+		return []error{attributeError("min_consul_version", err)}
+	}
+
+	return []error{d.SetNew("planned_upgrade_path", upgradePath)}
+}
+
+// clusterUpgradePath returns the Consul versions a cluster on the current version has to be upgraded
+// to, in order, to reach the target version.
+func clusterUpgradePath(ctx context.Context, meta interface{}, managedApp managedapplications.Application, current, target string) ([]string, error) {
+	upgradeVersionsResponse, err := meta.(*clients.Client).CustomResourceProvider.ListUpgradeVersions(ctx, *managedApp.ManagedResourceGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve upgrade versions for HCS cluster (Managed Application ID %q): %v", *managedApp.ID, err)
+	}
+
+	upgradeVersions := consul.FromAMAVersions(upgradeVersionsResponse.Versions)
+	if len(upgradeVersions) == 0 {
+		return nil, fmt.Errorf("no upgrade versions of Consul are available for this cluster; you may already be on the latest Consul version supported by HCS")
+	}
+
+	// A direct upgrade does not require the versions available on HCP.
+	if v, ok := consul.FindVersion(target, upgradeVersions); ok {
+		return []string{v.Version}, nil
+	}
+
+	availableConsulVersions, err := consul.GetAvailableHCPConsulVersions(ctx, meta.(*clients.Client).Config.HCPApiDomain)
+	if err != nil || availableConsulVersions == nil {
+		return nil, fmt.Errorf("%w: %v", errUnavailableConsulVersions, err)
+	}
+
+	path, err := consul.UpgradePath(current, target, upgradeVersions, availableConsulVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	upgradePath := make([]string, 0, len(path))
+	for _, v := range path {
+		upgradePath = append(upgradePath, v.Version)
+	}
+
+	return upgradePath, nil
+}
+
 func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	managedAppName := d.Get("managed_application_name").(string)
 	resourceGroupName := d.Get("resource_group_name").(string)
 
+	// Resolve the PGP key before creating the cluster, so that the root token can be encrypted
+	pgpKey, err := resolvePGPKey(d.Get("pgp_key").(string), d.Get("keybase_public_keys").(map[string]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	managedAppClient := meta.(*clients.Client).ManagedApplication
 
 	// Ensure a managed app with the same name does not exist in this resource group
@@ -350,11 +669,12 @@ func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta int
 	location := resourceGroup.Location
 	v, ok := d.GetOk("location")
 	if ok {
-		location = helper.String(strings.ReplaceAll(strings.ToLower(v.(string)), " ", ""))
+		location = helper.String(normalizeLocation(v.(string)))
 	}
-	supportedRegions, err := hcsmeta.GetSupportedRegions(ctx)
-	if err != nil {
-		return diag.Errorf("unable to retrieve supported HCS regions: %+v", err)
+	supportedRegions, err := meta.(*clients.Client).Meta.GetSupportedRegions(ctx)
+	diags := helper.MetaDiagnostics(err, "unable to retrieve supported HCS regions")
+	if diags.HasError() {
+		return diags
 	}
 	if !hcsmeta.RegionIsSupported(*location, supportedRegions) {
 		return diag.Errorf("unsupported location: %s; expected location to be one of %+v", *location, supportedRegions)
@@ -369,14 +689,18 @@ func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta int
 	if ok {
 		consulVersion = consul.NormalizeVersion(v.(string))
 	}
-	if !consul.IsValidVersion(consulVersion, availableConsulVersions) {
+	availableConsulVersion, ok := consul.FindVersion(consulVersion, availableConsulVersions)
+	if !ok {
 		return diag.Errorf("specified Consul version (%s) is unavailable; must be one of: %+v", consulVersion, availableConsulVersions)
 	}
+	// Use the version as listed by HCP, e.g. v1.9.5 for 1.9.5
+	consulVersion = availableConsulVersion.Version
 
 	// Azure Marketplace Plan
-	planDefaults, err := hcsmeta.GetPlanDefaults(ctx)
-	if err != nil {
-		return diag.Errorf("unable to retrieve HCS Azure Marketplace plan defaults: %+v", err)
+	planDefaults, err := meta.(*clients.Client).Meta.GetPlanDefaults(ctx)
+	diags = append(diags, helper.MetaDiagnostics(err, "unable to retrieve HCS Azure Marketplace plan defaults")...)
+	if diags.HasError() {
+		return diags
 	}
 
 	planName := planDefaults.Name
@@ -389,7 +713,7 @@ func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta int
 		Name:      helper.String(planName),
 		Version:   helper.String(planDefaults.Version),
 		Product:   helper.String(meta.(*clients.Client).Config.MarketPlaceProductName),
-		Publisher: helper.String("hashicorp-4665790"),
+		Publisher: helper.String(hcsMarketplacePublisher),
 	}
 
 	clusterName := managedAppName
@@ -416,7 +740,7 @@ func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta int
 		externalEndpoint = "enabled"
 	}
 
-	var federationToken string
+	federationToken := rawConfigString(d, "consul_federation_token_wo")
 	v, ok = d.GetOk("consul_federation_token")
 	if ok {
 		federationToken = v.(string)
@@ -498,7 +822,7 @@ func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta int
 		Location: location,
 		Tags:     tags,
 	}
-	future, err := managedAppClient.CreateOrUpdate(ctx, resourceGroupName, managedAppName, params)
+	future, err := createManagedApplication(ctx, meta, resourceGroupName, managedAppName, params)
 	if err != nil {
 		return diag.Errorf("unable to create HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
 			managedAppName,
@@ -536,11 +860,10 @@ func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta int
 
 	rootTokenResp, err := meta.(*clients.Client).CustomResourceProvider.CreateRootToken(ctx, *app.ApplicationProperties.ManagedResourceGroupID)
 	if err != nil {
-		return diag.Errorf("unable to create HCS cluster root token (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to create HCS cluster root token (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
 			managedAppName,
 			resourceGroupName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
@@ -549,15 +872,72 @@ func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta int
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	err = d.Set("consul_root_token_secret_id", rootTokenResp.MasterToken.SecretID)
+
+	// The secret ID is only stored encrypted if a PGP key is specified
+	secretID := rootTokenResp.MasterToken.SecretID
+	if pgpKey != "" {
+		encryptedSecretID, fingerprint, err := helper.EncryptWithPGPKey(pgpKey, secretID)
+		if err != nil {
+			return diag.Errorf("unable to encrypt HCS cluster root token (Managed Application %q) (Resource Group %q): %v",
+				managedAppName,
+				resourceGroupName,
+				err,
+			)
+		}
+
+		err = d.Set("consul_root_token_encrypted_secret_id", encryptedSecretID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = d.Set("consul_root_token_key_fingerprint", fingerprint)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		secretID = ""
+	}
+
+	err = d.Set("consul_root_token_secret_id", secretID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	return resourceClusterRead(ctx, d, meta)
+	return append(diags, resourceClusterRead(ctx, d, meta)...)
+}
+
+// createManagedApplication starts the creation of a Managed Application. Creating a Managed Application
+// with the ID of a recently deleted one fails with ResourcePurchaseCanceling until Azure has canceled
+// the Marketplace purchase of the deleted one, so the creation is retried with backoff in that case.
+func createManagedApplication(ctx context.Context, meta interface{}, resourceGroupName, managedAppName string,
+	params managedapplications.Application) (managedapplications.ApplicationsCreateOrUpdateFuture, error) {
+	managedAppClient := meta.(*clients.Client).ManagedApplication
+	backoff := meta.(*clients.Client).CustomResourceProvider.OperationPollConfig.Backoff()
+
+	for {
+		future, err := managedAppClient.CreateOrUpdate(ctx, resourceGroupName, managedAppName, params)
+		if err == nil || !helper.IsAzureServiceErrorCode(err, "ResourcePurchaseCanceling") {
+			return future, err
+		}
+
+		log.Printf("[INFO] the Marketplace purchase of a previously deleted HCS cluster is being canceled; retrying creation (Managed Application %q) (Resource Group %q)",
+			managedAppName,
+			resourceGroupName,
+		)
+
+		if backoffErr := backoff(ctx); backoffErr != nil {
+			return future, err
+		}
+	}
 }
 
 func resourceClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return readCluster(ctx, d, meta, false)
+}
+
+// readCluster reads the cluster into the state. A cluster created with consul_federation_token_wo has
+// an empty consul_federation_token in the state, which is kept on refresh so that the federation token
+// never ends up in the state. An imported cluster has no prior state, so its federation token is read.
+func readCluster(ctx context.Context, d *schema.ResourceData, meta interface{}, importing bool) diag.Diagnostics {
 	// Fetch the managed app
 	managedAppID := d.Id()
 	managedApp, err := meta.(*clients.Client).ManagedApplication.GetByID(ctx, managedAppID)
@@ -587,11 +967,10 @@ func resourceClusterRead(ctx context.Context, d *schema.ResourceData, meta inter
 	// Fetch the cluster managed resource
 	cluster, err := meta.(*clients.Client).CustomResourceProvider.FetchConsulCluster(ctx, *managedApp.ManagedResourceGroupID, clusterName)
 	if err != nil {
-		return diag.Errorf("unable to fetch HCS cluster (Managed Application ID %q) (Cluster Name %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to fetch HCS cluster (Managed Application ID %q) (Cluster Name %q) (Correlation ID %q)",
 			managedAppID,
 			clusterName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
@@ -614,7 +993,37 @@ func resourceClusterRead(ctx context.Context, d *schema.ResourceData, meta inter
 		)
 	}
 
-	return setClusterData(d, managedApp, cluster, vNet)
+	priorFederationToken := d.Get("consul_federation_token").(string)
+
+	diags := setClusterData(d, managedApp, cluster, vNet)
+	if diags.HasError() {
+		return diags
+	}
+
+	if !importing && priorFederationToken == "" {
+		err = d.Set("consul_federation_token", "")
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+// rawConfigString returns the value of a string attribute in the raw config, or an empty string if it
+// is not set. Write-only attributes are never stored in the plan or state and can only be read this way.
+func rawConfigString(d *schema.ResourceData, name string) string {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.Type().HasAttribute(name) {
+		return ""
+	}
+
+	v := config.GetAttr(name)
+	if v.IsNull() || !v.IsKnown() {
+		return ""
+	}
+
+	return v.AsString()
 }
 
 func toModelBoolean(b bool) models.HashicorpCloudConsulamaAmaBoolean {
@@ -663,14 +1072,36 @@ func resourceClusterUpdate(ctx context.Context, d *schema.ResourceData, meta int
 	}
 
 	// If the min_consul_version differs from the current version, attempt to upgrade the cluster
-	versionChanged := d.HasChange("min_consul_version")
-	if versionChanged {
-		update.ConsulVersion = d.Get("min_consul_version").(string)
-	}
+	// through each version of the upgrade path in order. The audit logging configuration is
+	// updated along with the first upgrade.
+	if d.HasChange("min_consul_version") {
+		upgradePath, diags := clusterUpgradePathForUpdate(ctx, d, meta, managedApp)
+		if diags != nil {
+			return diags
+		}
 
-	// Only execute the UpdateCluster custom action on the managed app if the audit logging
-	// configuration or the Consul version has been changed.
-	if versionChanged || auditLoggingChanged {
+		// The Consul version of the cluster before the first upgrade, and after each verified upgrade
+		upgradedVersion := d.Get("consul_version").(string)
+
+		for i, consulVersion := range upgradePath {
+			if i > 0 {
+				update = &models.HashicorpCloudConsulamaAmaClusterUpdate{}
+			}
+			update.ConsulVersion = consulVersion
+
+			if diags := upgradeCluster(ctx, meta, managedApp, update); diags != nil {
+				return append(diags, setClusterUpgradeProgress(d, upgradedVersion, upgradePath[i:])...)
+			}
+
+			if diags := verifyClusterVersion(ctx, d, meta, managedApp, consulVersion); diags != nil {
+				return append(diags, setClusterUpgradeProgress(d, upgradedVersion, upgradePath[i:])...)
+			}
+
+			upgradedVersion = consulVersion
+		}
+	} else if auditLoggingChanged {
+		// Only execute the UpdateCluster custom action on the managed app if the audit logging
+		// configuration has been changed.
 		if err := upgradeCluster(ctx, meta, managedApp, update); err != nil {
 			return err
 		}
@@ -688,6 +1119,95 @@ func resourceClusterUpdate(ctx context.Context, d *schema.ResourceData, meta int
 	return resourceClusterRead(ctx, d, meta)
 }
 
+// clusterUpgradePathForUpdate returns the planned upgrade path of the cluster. The path is planned
+// again if it is unknown during plan or does not lead to the min_consul_version.
+func clusterUpgradePathForUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}, managedApp managedapplications.Application) ([]string, diag.Diagnostics) {
+	target := d.Get("min_consul_version").(string)
+
+	var upgradePath []string
+	for _, v := range d.Get("planned_upgrade_path").([]interface{}) {
+		upgradePath = append(upgradePath, v.(string))
+	}
+
+	if len(upgradePath) > 0 && consul.EqualVersions(upgradePath[len(upgradePath)-1], target) {
+		return upgradePath, nil
+	}
+
+	cluster, err := meta.(*clients.Client).CustomResourceProvider.FetchConsulCluster(ctx, *managedApp.ManagedResourceGroupID, d.Get("cluster_name").(string))
+	if err != nil {
+		return nil, helper.ErrorDiagnostics(err, "unable to fetch HCS cluster (Managed Application ID %q) (Correlation ID %q)",
+			*managedApp.ID,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	upgradePath, err = clusterUpgradePath(ctx, meta, managedApp, cluster.Properties.ConsulCurrentVersion, target)
+	if err != nil {
+		return nil, diag.Errorf("unable to plan the upgrade of HCS cluster (Managed Application ID %q) to Consul version %s (Correlation ID %q): %v",
+			*managedApp.ID,
+			target,
+			meta.(*clients.Client).CorrelationRequestID,
+			err,
+		)
+	}
+
+	if err := d.Set("planned_upgrade_path", upgradePath); err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	return upgradePath, nil
+}
+
+// verifyClusterVersion checks that the cluster reports the Consul version it has been upgraded to
+// and records the version in the state.
+func verifyClusterVersion(ctx context.Context, d *schema.ResourceData, meta interface{}, managedApp managedapplications.Application, consulVersion string) diag.Diagnostics {
+	cluster, err := meta.(*clients.Client).CustomResourceProvider.FetchConsulCluster(ctx, *managedApp.ManagedResourceGroupID, d.Get("cluster_name").(string))
+	if err != nil {
+		return helper.ErrorDiagnostics(err, "unable to fetch HCS cluster (Managed Application ID %q) (Correlation ID %q)",
+			*managedApp.ID,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	if !consul.EqualVersions(cluster.Properties.ConsulCurrentVersion, consulVersion) {
+		return diag.Errorf("HCS cluster (Managed Application ID %q) reports Consul version %s after the upgrade to %s (Correlation ID %q)",
+			*managedApp.ID,
+			cluster.Properties.ConsulCurrentVersion,
+			consulVersion,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	if err := d.Set("consul_version", cluster.Properties.ConsulCurrentVersion); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("min_consul_version", cluster.Properties.ConsulCurrentVersion); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// setClusterUpgradeProgress records an upgrade that failed partway in the state. The cluster is
+// on the Consul version of the last verified upgrade, and the versions it has not been upgraded to
+// remain in the planned_upgrade_path, so that the next plan upgrades it to the min_consul_version.
+func setClusterUpgradeProgress(d *schema.ResourceData, upgradedVersion string, remainingPath []string) diag.Diagnostics {
+	if err := d.Set("consul_version", upgradedVersion); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("min_consul_version", upgradedVersion); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("planned_upgrade_path", remainingPath); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
 // upgradeClusterVersion updates a cluster's Consul version to a valid upgrade version
 func upgradeCluster(ctx context.Context, meta interface{}, managedApp managedapplications.Application, update *models.HashicorpCloudConsulamaAmaClusterUpdate) diag.Diagnostics {
 	if update.ConsulVersion != "" {
@@ -697,10 +1217,9 @@ func upgradeCluster(ctx context.Context, meta interface{}, managedApp managedapp
 		// Retrieve the valid upgrade versions
 		upgradeVersionsResponse, err := meta.(*clients.Client).CustomResourceProvider.ListUpgradeVersions(ctx, *managedApp.ManagedResourceGroupID)
 		if err != nil {
-			return diag.Errorf("unable to retrieve upgrade versions for HCS cluster (Managed Application ID %q) (Correlation ID %q): %v",
+			return helper.ErrorDiagnostics(err, "unable to retrieve upgrade versions for HCS cluster (Managed Application ID %q) (Correlation ID %q)",
 				*managedApp.ID,
 				meta.(*clients.Client).CorrelationRequestID,
-				err,
 			)
 		}
 
@@ -708,28 +1227,28 @@ func upgradeCluster(ctx context.Context, meta interface{}, managedApp managedapp
 			return diag.Errorf("no upgrade versions of Consul are available for this cluster; you may already be on the latest Consul version supported by HCS")
 		}
 
-		if !consul.IsValidVersion(update.ConsulVersion, consul.FromAMAVersions(upgradeVersionsResponse.Versions)) {
+		upgradeVersion, ok := consul.FindVersion(update.ConsulVersion, consul.FromAMAVersions(upgradeVersionsResponse.Versions))
+		if !ok {
 			return diag.Errorf("specified Consul version (%s) is unavailable; must be one of: %+v", update.ConsulVersion, consul.FromAMAVersions(upgradeVersionsResponse.Versions))
 		}
+		update.ConsulVersion = upgradeVersion.Version
 	}
 
 	updateResponse, err := meta.(*clients.Client).CustomResourceProvider.UpdateCluster(ctx, *managedApp.ManagedResourceGroupID, update)
 	if err != nil {
-		return diag.Errorf("unable to update HCS cluster (Managed Application ID %q) (Consul Version %s) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to update HCS cluster (Managed Application ID %q) (Consul Version %s) (Correlation ID %q)",
 			*managedApp.ID,
 			update.ConsulVersion,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
-	err = meta.(*clients.Client).CustomResourceProvider.PollOperation(ctx, updateResponse.Operation.ID, *managedApp.ManagedResourceGroupID, *managedApp.Name, 10)
+	err = meta.(*clients.Client).CustomResourceProvider.PollOperation(ctx, updateResponse.Operation.ID, *managedApp.ManagedResourceGroupID, *managedApp.Name)
 	if err != nil {
-		return diag.Errorf("unable to poll update cluster operation (Managed Application ID %q) (Consul Version %s) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to poll update cluster operation (Managed Application ID %q) (Consul Version %s) (Correlation ID %q)",
 			*managedApp.ID,
 			update.ConsulVersion,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
@@ -797,7 +1316,15 @@ func resourceClusterDelete(ctx context.Context, d *schema.ResourceData, meta int
 	federationResponse, err := meta.(*clients.Client).CustomResourceProvider.GetFederation(ctx, *managedApp.ManagedResourceGroupID, d.Get("resource_group_name").(string))
 	// Ensure the cluster is not the primary in the federation
 	if err == nil && isClusterPrimaryInFederation(*managedApp.Name, resourceGroupName, federationResponse) {
-		return diag.Errorf("unable to delete primary datacenter of a federation before all secondary datacenters are deleted: (Managed Application %q) (Resource Group %q)", *managedApp.Name, resourceGroupName)
+		v, ok := d.GetOk("federation_delete_wait")
+		if !ok {
+			return diag.Errorf("unable to delete primary datacenter of a federation before all secondary datacenters are deleted: (Managed Application %q) (Resource Group %q)", *managedApp.Name, resourceGroupName)
+		}
+
+		wait, _ := time.ParseDuration(v.(string))
+		if diags := waitForFederationSecondariesDeleted(ctx, meta, managedApp, resourceGroupName, federationResponse, wait); diags != nil {
+			return diags
+		}
 	}
 
 	// Delete the managed app (the cluster custom resource will be deleted as well).
@@ -819,13 +1346,117 @@ func resourceClusterDelete(ctx context.Context, d *schema.ResourceData, meta int
 		)
 	}
 
-	// Sleep to prevent ResourcePurchaseCanceling errors returned from Azure during the scenario when
-	// a cluster resource must be deleted and re-created.
-	time.Sleep(time.Minute)
+	// Wait for Azure to finish the deletion to prevent ResourcePurchaseCanceling errors during the
+	// scenario when a cluster resource must be deleted and re-created.
+	return waitForClusterDeleted(ctx, meta, managedApp)
+}
+
+// waitForClusterDeleted polls the Managed Application and its Managed Resource Group until Azure
+// reports both as deleted, or the context is done. Azure deletes the Managed Resource Group once the
+// Marketplace purchase of the Managed Application has been canceled. The purchase itself cannot be
+// queried, so creating a cluster retries on ResourcePurchaseCanceling errors as well.
+func waitForClusterDeleted(ctx context.Context, meta interface{}, managedApp managedapplications.Application) diag.Diagnostics {
+	managedAppClient := meta.(*clients.Client).ManagedApplication
+	resourceGroupClient := meta.(*clients.Client).ResourceGroup
+	managedResourceGroupName := helper.ParseResourceNameFromID(*managedApp.ManagedResourceGroupID)
+	backoff := meta.(*clients.Client).CustomResourceProvider.OperationPollConfig.Backoff()
+
+	for {
+		app, err := managedAppClient.GetByID(ctx, *managedApp.ID)
+		if err == nil || !helper.IsAutoRestResponseCodeNotFound(app.Response) {
+			log.Printf("[INFO] waiting for deletion of HCS cluster (Managed Application ID %q)", *managedApp.ID)
+		} else {
+			managedResourceGroup, err := resourceGroupClient.Get(ctx, managedResourceGroupName)
+			if err != nil && helper.IsAutoRestResponseCodeNotFound(managedResourceGroup.Response) {
+				return nil
+			}
+
+			log.Printf("[INFO] waiting for deletion of the Managed Resource Group and cancellation of the Marketplace purchase of HCS cluster (Managed Application ID %q) (Managed Resource Group %q)",
+				*managedApp.ID,
+				managedResourceGroupName,
+			)
+		}
+
+		if err := backoff(ctx); err != nil {
+			return diag.Errorf("timed out waiting for deletion of HCS cluster (Managed Application ID %q) (Managed Resource Group %q) (Correlation ID %q): %v",
+				*managedApp.ID,
+				managedResourceGroupName,
+				meta.(*clients.Client).CorrelationRequestID,
+				err,
+			)
+		}
+	}
+}
+
+// waitForFederationSecondariesDeleted polls the federation of a primary cluster with the operation poll
+// backoff until all of its secondary clusters are deleted or the wait duration elapses.
+func waitForFederationSecondariesDeleted(ctx context.Context, meta interface{}, managedApp managedapplications.Application, resourceGroupName string,
+	federationResponse models.HashicorpCloudConsulamaAmaGetFederationResponse, wait time.Duration) diag.Diagnostics {
+	crpClient := meta.(*clients.Client).CustomResourceProvider
+	backoff := crpClient.OperationPollConfig.Backoff()
+	deadline := time.Now().Add(wait)
+
+	for isClusterPrimaryInFederation(*managedApp.Name, resourceGroupName, federationResponse) {
+		secondaries := federatedClusterNames(federationResponse.SecondaryDatacenters)
+		if time.Now().After(deadline) {
+			return diag.Errorf("timed out after %s waiting for the secondary datacenters of the federation to be deleted before deleting the primary datacenter (Managed Application %q) (Resource Group %q) (Secondary Datacenters %s)",
+				wait,
+				*managedApp.Name,
+				resourceGroupName,
+				strings.Join(secondaries, ", "),
+			)
+		}
+
+		log.Printf("[INFO] waiting for %d secondary datacenters of the federation to be deleted before deleting the primary datacenter (Managed Application %q) (Resource Group %q) (Secondary Datacenters %s)",
+			len(secondaries),
+			*managedApp.Name,
+			resourceGroupName,
+			strings.Join(secondaries, ", "),
+		)
+
+		if err := backoff(ctx); err != nil {
+			return diag.Errorf("unable to wait for the secondary datacenters of the federation to be deleted before deleting the primary datacenter (Managed Application %q) (Resource Group %q) (Secondary Datacenters %s): %v",
+				*managedApp.Name,
+				resourceGroupName,
+				strings.Join(secondaries, ", "),
+				err,
+			)
+		}
+
+		var err error
+		federationResponse, err = crpClient.GetFederation(ctx, *managedApp.ManagedResourceGroupID, resourceGroupName)
+		if err != nil {
+			// The federation no longer exists once its last secondary datacenter is deleted
+			if crpClient.IsCRPErrorAzureNotFound(err) {
+				break
+			}
+
+			return helper.ErrorDiagnostics(err, "unable to fetch federation of HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+				*managedApp.Name,
+				resourceGroupName,
+				meta.(*clients.Client).CorrelationRequestID,
+			)
+		}
+	}
+
+	log.Printf("[INFO] all secondary datacenters of the federation have been deleted (Managed Application %q) (Resource Group %q)", *managedApp.Name, resourceGroupName)
 
 	return nil
 }
 
+// federatedClusterNames returns the resource group and managed application names of the clusters
+// of a federation.
+func federatedClusterNames(clusters []*models.HashicorpCloudConsulamaAmaFederatedClusterResponse) []string {
+	names := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		if c != nil {
+			names = append(names, c.ResourceGroup+"/"+c.Name)
+		}
+	}
+
+	return names
+}
+
 // isClusterPrimaryInFederation determines if a cluster's managed app and resource group names match
 // the primary cluster's managed app and resource group names in a non-empty federation.
 func isClusterPrimaryInFederation(managedAppName string, resourceGroupName string, federationResponse models.HashicorpCloudConsulamaAmaGetFederationResponse) bool {
@@ -851,7 +1482,7 @@ func resourceClusterImport(ctx context.Context, d *schema.ResourceData, meta int
 	d.SetId(id)
 	d.Set("cluster_name", clusterName)
 
-	diags := resourceClusterRead(ctx, d, meta)
+	diags := readCluster(ctx, d, meta, true)
 	if err := helper.ToError(diags); err != nil {
 		return nil, err
 	}
@@ -886,6 +1517,16 @@ func validateClusterImportString(s string) (string, string, error) {
 	return segments[0], segments[1], nil
 }
 
+// clusterModeFromProperties determines the cluster mode based on the number of Consul servers.
+// TODO: cluster.Properties.ConsulClusterMode should be relied on when the value is populated on the fetch response
+func clusterModeFromProperties(properties *models.HashicorpCloudConsulamaAmaClusterProperties) string {
+	if properties.ConsulNumServers == "1" {
+		return "DEVELOPMENT"
+	}
+
+	return "PRODUCTION"
+}
+
 // setClusterData sets the KV pairs of the cluster resource schema.
 // We do not set consul_root_token_accessor_id and consul_root_token_secret_id here since
 // the original root token is only available during cluster creation.
@@ -909,14 +1550,7 @@ func setClusterData(d *schema.ResourceData, managedApp managedapplications.Appli
 		return diag.FromErr(err)
 	}
 
-	// Set cluster mode based on numServers
-	// TODO: cluster.Properties.ConsulClusterMode should be relied on when the value is populated on the fetch response
-	clusterMode := "PRODUCTION"
-	if cluster.Properties.ConsulNumServers == "1" {
-		clusterMode = "DEVELOPMENT"
-	}
-
-	err = d.Set("cluster_mode", clusterMode)
+	err = d.Set("cluster_mode", clusterModeFromProperties(cluster.Properties))
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -1046,6 +1680,11 @@ func setClusterData(d *schema.ResourceData, managedApp managedapplications.Appli
 		return diag.FromErr(err)
 	}
 
+	err = setClusterConsulConfigData(d, cluster.Properties.ConsulConfigFile, cluster.Properties.ConsulCaFile)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	err = d.Set("consul_connect", strings.ToLower(cluster.Properties.ConsulConnect) == "enabled")
 	if err != nil {
 		return diag.FromErr(err)
@@ -1085,3 +1724,49 @@ func setClusterData(d *schema.ResourceData, managedApp managedapplications.Appli
 
 	return nil
 }
+
+// setClusterConsulConfigData sets the attributes decoded from the Base64 encoded Consul config
+// and CA file of a cluster. They are left empty while the cluster has not been provisioned yet.
+func setClusterConsulConfigData(d *schema.ResourceData, encodedConfig, encodedCAFile string) error {
+	if encodedConfig == "" || encodedCAFile == "" {
+		return nil
+	}
+
+	consulConfig, err := clients.DecodeConsulConfig(encodedConfig, encodedCAFile)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set("consul_retry_join", consulConfig.RetryJoin)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set("consul_gossip_encryption_key", consulConfig.GossipKey)
+	if err != nil {
+		return err
+	}
+
+	err = d.Set("consul_ca_pem", consulConfig.CaFile)
+	if err != nil {
+		return err
+	}
+
+	// The CA certificate details are informational, so a CA that cannot be parsed must not
+	// fail reading the cluster.
+	caCert, err := helper.ParseCACertificate(consulConfig.CaFile)
+	if err != nil {
+		log.Printf("[WARN] unable to parse the Consul CA certificate of the HCS cluster; leaving consul_ca_certificate empty: %v", err)
+		return d.Set("consul_ca_certificate", nil)
+	}
+
+	return d.Set("consul_ca_certificate", []interface{}{
+		map[string]interface{}{
+			"subject":            caCert.Subject,
+			"serial_number":      caCert.SerialNumber,
+			"sha256_fingerprint": caCert.SHA256Fingerprint,
+			"not_before":         caCert.NotBefore,
+			"not_after":          caCert.NotAfter,
+		},
+	})
+}
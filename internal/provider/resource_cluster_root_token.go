@@ -35,10 +35,13 @@ data:
 func resourceClusterRootToken() *schema.Resource {
 	return &schema.Resource{
 		Description: "The cluster root token resource is the token used to bootstrap the cluster's ACL system." +
-			" Using this resource to create a new root token for an cluster resource will invalidate the consul root token accessor id and Consul root token secret id properties of the cluster.",
+			" Using this resource to create a new root token for an cluster resource will invalidate the consul root token accessor id and Consul root token secret id properties of the cluster." +
+			" The `hcs_cluster_root_token` ephemeral resource creates a new root token each time it is opened, including during plan, which invalidates the root token of this resource as well.",
 		CreateContext: resourceClusterRootTokenCreate,
 		ReadContext:   resourceClusterRootTokenRead,
+		UpdateContext: resourceClusterRootTokenUpdate,
 		DeleteContext: resourceClusterRootTokenDelete,
+		CustomizeDiff: resourceClusterRootTokenCustomizeDiff,
 		Timeouts: &schema.ResourceTimeout{
 			Default: &defaultClusterRootTokenTimeoutDuration,
 		},
@@ -58,6 +61,38 @@ func resourceClusterRootToken() *schema.Resource {
 				ForceNew:         true,
 				ValidateDiagFunc: validateSlugID,
 			},
+			// Optional inputs
+			"rotation_days": {
+				Description:      "The number of days after which the root token expires. An expired root token is removed from the state on refresh, so that the next apply rotates it by creating a new one. If not specified, the root token does not expire.",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validateIntAtLeast(1),
+			},
+			"pgp_key": {
+				Description:      "A base64 encoded or ASCII armored PGP public key used to encrypt the secret ID of the root ACL token, or a Keybase reference of the form `keybase:<username>`, which is resolved from `keybase_public_keys`. If specified, `encrypted_secret_id` is set instead of `secret_id` and `kubernetes_secret`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validatePGPKey,
+			},
+			"keybase_public_keys": {
+				Description: "A map of Keybase usernames to their PGP public keys, as exported with `keybase pgp export -q <username>`. A `keybase:<username>` reference in `pgp_key` is resolved to the key of the username. The keys are not fetched from keybase.io.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"rotate_when_changed": {
+				Description: "An arbitrary map of values that, when changed, rotates the root token by creating a new one.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			// Computed outputs
 			"accessor_id": {
 				Description: "The accessor ID of the root ACL token.",
@@ -76,6 +111,31 @@ func resourceClusterRootToken() *schema.Resource {
 				Computed:    true,
 				Sensitive:   true,
 			},
+			"encrypted_secret_id": {
+				Description: "The secret ID of the root ACL token encrypted with `pgp_key` and base64 encoded. It can be decrypted with `base64 --decode | gpg --decrypt`. Empty if `pgp_key` is not specified.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"key_fingerprint": {
+				Description: "The fingerprint of the PGP key used to encrypt `encrypted_secret_id`. Empty if `pgp_key` is not specified.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"created_at": {
+				Description: "The time the root token was created as an RFC 3339 timestamp.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"expires_at": {
+				Description: "The time the root token expires as an RFC 3339 timestamp. Empty if `rotation_days` is not specified.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"consul_cluster_id": {
+				Description: "The ID of the cluster the root token was created for. If the cluster is re-created, the root token is removed from the state on refresh, so that the next apply creates a new one.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -85,6 +145,12 @@ func resourceClusterRootTokenCreate(ctx context.Context, d *schema.ResourceData,
 	resourceGroupName := d.Get("resource_group_name").(string)
 	managedAppName := d.Get("managed_application_name").(string)
 
+	// Resolve the PGP key before creating the root token, which invalidates the previous one
+	pgpKey, err := resolvePGPKey(d.Get("pgp_key").(string), d.Get("keybase_public_keys").(map[string]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	managedAppClient := meta.(*clients.Client).ManagedApplication
 	app, err := managedAppClient.Get(ctx, resourceGroupName, managedAppName)
 	if err != nil {
@@ -107,41 +173,90 @@ func resourceClusterRootTokenCreate(ctx context.Context, d *schema.ResourceData,
 
 	mrgID := *app.ApplicationProperties.ManagedResourceGroupID
 
+	clusterID, err := fetchRootTokenClusterID(ctx, meta, mrgID)
+	if err != nil {
+		return helper.ErrorDiagnostics(err, "unable to fetch HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
 	crpClient := meta.(*clients.Client).CustomResourceProvider
 	rootTokenResp, err := crpClient.CreateRootToken(ctx, mrgID)
 	if err != nil {
-		return diag.Errorf("unable to create HCS cluster root token (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to create HCS cluster root token (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
 			managedAppName,
 			resourceGroupName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
+	// set the id to the value of the accessor id before encrypting the secret id, so that the
+	// token is still invalidated on destroy if the encryption fails
+	d.SetId(rootTokenResp.MasterToken.AccessorID)
+
 	err = d.Set("accessor_id", rootTokenResp.MasterToken.AccessorID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	secretID := rootTokenResp.MasterToken.SecretID
-	err = d.Set("secret_id", secretID)
+	if pgpKey != "" {
+		// The secret ID is only stored encrypted if a PGP key is specified
+		encryptedSecretID, fingerprint, err := helper.EncryptWithPGPKey(pgpKey, secretID)
+		if err != nil {
+			return diag.Errorf("unable to encrypt HCS cluster root token (Managed Application %q) (Resource Group %q): %v",
+				managedAppName,
+				resourceGroupName,
+				err,
+			)
+		}
+
+		err = d.Set("encrypted_secret_id", encryptedSecretID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = d.Set("key_fingerprint", fingerprint)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		err = d.Set("secret_id", secretID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = d.Set("kubernetes_secret", generateKubernetesSecret(secretID, managedAppName))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	createdAt := time.Now().UTC()
+	err = d.Set("created_at", createdAt.Format(time.RFC3339))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	err = d.Set("kubernetes_secret", generateKubernetesSecret(secretID, managedAppName))
+	err = d.Set("expires_at", rootTokenExpiresAt(createdAt, d.Get("rotation_days").(int)))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	// set the id to the value of the accessor id
-	d.SetId(rootTokenResp.MasterToken.AccessorID)
+	err = d.Set("consul_cluster_id", clusterID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
 
-// resourceClusterRootTokenRead will act as a no-op as the root token is not persisted in
-// any way that it can be fetched and read
+// resourceClusterRootTokenRead removes the root token from the state if it has expired or the cluster
+// it was created for no longer exists, so that the next apply creates a new one. The root token itself
+// is not persisted in any way that it can be fetched and read, so a root token that has been replaced
+// outside of Terraform cannot be detected.
 func resourceClusterRootTokenRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	resourceGroupName := d.Get("resource_group_name").(string)
 	managedAppName := d.Get("managed_application_name").(string)
@@ -168,9 +283,119 @@ func resourceClusterRootTokenRead(ctx context.Context, d *schema.ResourceData, m
 		)
 	}
 
+	if expiresAt := d.Get("expires_at").(string); expiresAt != "" {
+		expiry, err := time.Parse(time.RFC3339, expiresAt)
+		if err == nil && !time.Now().Before(expiry) {
+			log.Printf("[INFO] root token expired at %s (Managed Application %q) (Resource Group %q); removing root token to rotate it.",
+				expiresAt,
+				managedAppName,
+				resourceGroupName,
+			)
+			d.SetId("")
+			return nil
+		}
+	}
+
+	clusterID, err := fetchRootTokenClusterID(ctx, meta, *managedApp.ManagedResourceGroupID)
+	if err != nil {
+		return helper.ErrorDiagnostics(err, "unable to fetch HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	// Root tokens created by previous versions of the provider do not record the cluster ID
+	if previousClusterID := d.Get("consul_cluster_id").(string); previousClusterID == "" {
+		if err := d.Set("consul_cluster_id", clusterID); err != nil {
+			return diag.FromErr(err)
+		}
+	} else if previousClusterID != clusterID {
+		log.Printf("[WARN] the HCS cluster the root token was created for (Cluster ID %q) no longer exists (Managed Application %q) (Resource Group %q) (Correlation ID %q); removing root token.",
+			previousClusterID,
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+// resourceClusterRootTokenUpdate updates the expiry of the root token when its rotation_days change.
+func resourceClusterRootTokenUpdate(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	if d.HasChange("rotation_days") {
+		createdAt, err := time.Parse(time.RFC3339, d.Get("created_at").(string))
+		if err != nil {
+			return diag.Errorf("unable to parse the creation time of the root token: %v", err)
+		}
+
+		err = d.Set("expires_at", rootTokenExpiresAt(createdAt, d.Get("rotation_days").(int)))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// Don't read the token back, which would remove it from the state if the new rotation period
+	// has already expired it. The next refresh removes it so that the token is rotated.
+	return nil
+}
+
+// resourceClusterRootTokenCustomizeDiff marks the expiry of the root token as changing when its
+// rotation_days change, and checks that a Keybase reference in pgp_key can be resolved.
+func resourceClusterRootTokenCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if err := validateKeybasePGPKey(d); err != nil {
+		return err
+	}
+
+	if d.Id() != "" && d.HasChange("rotation_days") {
+		return d.SetNewComputed("expires_at")
+	}
+
+	return nil
+}
+
+// validateKeybasePGPKey checks that a Keybase reference in pgp_key resolves to a valid PGP public key
+// in keybase_public_keys.
+func validateKeybasePGPKey(d *schema.ResourceDiff) error {
+	pgpKey := d.Get("pgp_key").(string)
+	if _, ok := helper.KeybaseUsername(pgpKey); !ok || !d.NewValueKnown("pgp_key") || !d.NewValueKnown("keybase_public_keys") {
+		return nil
+	}
+
+	if _, err := resolvePGPKey(pgpKey, d.Get("keybase_public_keys").(map[string]interface{})); err != nil {
+		return attributeError("pgp_key", err)
+	}
+
 	return nil
 }
 
+// resolvePGPKey resolves a Keybase reference in the pgp_key argument to the exported key of the user
+// in the keybase_public_keys argument, and checks that the key can be parsed.
+func resolvePGPKey(pgpKey string, keybasePublicKeys map[string]interface{}) (string, error) {
+	if pgpKey == "" {
+		return "", nil
+	}
+
+	keybaseKeys := make(map[string]string, len(keybasePublicKeys))
+	for username, key := range keybasePublicKeys {
+		keybaseKeys[username] = key.(string)
+	}
+
+	resolvedKey, err := helper.ResolvePGPKey(pgpKey, keybaseKeys)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := helper.ParsePGPKey(resolvedKey); err != nil {
+		return "", fmt.Errorf("invalid PGP public key for %s: %v", pgpKey, err)
+	}
+
+	return resolvedKey, nil
+}
+
 // resourceClusterRootTokenDelete will "delete" an existing token by creating a new one,
 // that will not be returned, and invalidating the previous token for the cluster.
 func resourceClusterRootTokenDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -204,17 +429,42 @@ func resourceClusterRootTokenDelete(ctx context.Context, d *schema.ResourceData,
 	// generate a new token to invalidate the previous one, but discard the response
 	_, err = crpClient.CreateRootToken(ctx, mrgID)
 	if err != nil {
-		return diag.Errorf("unable to delete HCS cluster root token (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to delete HCS cluster root token (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
 			managedAppName,
 			resourceGroupName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
 	return nil
 }
 
+// rootTokenExpiresAt returns the RFC 3339 timestamp at which a root token created at the passed
+// time expires, or an empty string if it does not expire.
+func rootTokenExpiresAt(createdAt time.Time, rotationDays int) string {
+	if rotationDays <= 0 {
+		return ""
+	}
+
+	return createdAt.AddDate(0, 0, rotationDays).Format(time.RFC3339)
+}
+
+// fetchRootTokenClusterID returns the ID of the cluster in the passed Managed Resource Group.
+func fetchRootTokenClusterID(ctx context.Context, meta interface{}, managedResourceGroupID string) (string, error) {
+	resp, err := meta.(*clients.Client).CustomResourceProvider.ListConsulClusters(ctx, managedResourceGroupID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, cluster := range resp.Value {
+		if cluster != nil && cluster.Properties != nil {
+			return cluster.Properties.ConsulClusterID, nil
+		}
+	}
+
+	return "", nil
+}
+
 // generateKubernetesSecret will generate a Kubernetes secret with
 // a base64 encoded root token secret as it's token.
 func generateKubernetesSecret(rootTokenSecretId, managedAppName string) string {
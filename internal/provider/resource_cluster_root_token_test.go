@@ -4,33 +4,177 @@
 package provider
 
 import (
-	"regexp"
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
 )
 
-func TestClusterRootToken(t *testing.T) {
-	t.Skip("resource not yet implemented, remove this once you add your own code")
-
-	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: providerFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: testClusterRootToken,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestMatchResourceAttr(
-						"scaffolding_resource.foo", "sample_attribute", regexp.MustCompile("^ba")),
-				),
-			},
+func TestResourceClusterRootToken_lifecycle(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	clusterState := testCreateCluster(t, client)
+	res := resourceClusterRootToken()
+
+	config := map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+	}
+
+	// Create
+	state := testApplyResource(t, res, nil, config, client)
+	accessorID := state.ID
+	r.Equal(server.RootTokenAccessorID(clusterState.ID), accessorID)
+	r.NotEqual(clusterState.Attributes["consul_root_token_accessor_id"], accessorID)
+	r.Equal(accessorID, state.Attributes["accessor_id"])
+	r.NotEmpty(state.Attributes["secret_id"])
+	r.NotEmpty(state.Attributes["kubernetes_secret"])
+
+	// Read
+	state = testRefreshResource(t, res, state, client)
+	r.Equal(accessorID, state.ID)
+
+	// Delete invalidates the token by creating a new one
+	testDestroyResource(t, res, state, client)
+	r.NotEmpty(server.RootTokenAccessorID(clusterState.ID))
+	r.NotEqual(accessorID, server.RootTokenAccessorID(clusterState.ID))
+}
+
+func TestResourceClusterRootToken_rotation(t *testing.T) {
+	r := require.New(t)
+
+	_, client := testFakeServer(t)
+	clusterState := testCreateCluster(t, client)
+	res := resourceClusterRootToken()
+
+	config := map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"rotation_days":            30,
+		"rotate_when_changed": map[string]interface{}{
+			"version": "1",
 		},
-	})
+	}
+
+	state := testApplyResource(t, res, nil, config, client)
+	accessorID := state.ID
+	createdAt, err := time.Parse(time.RFC3339, state.Attributes["created_at"])
+	r.NoError(err)
+	r.WithinDuration(time.Now(), createdAt, time.Minute)
+	r.Equal(createdAt.AddDate(0, 0, 30).Format(time.RFC3339), state.Attributes["expires_at"])
+	r.Equal(clusterState.Attributes["consul_cluster_id"], state.Attributes["consul_cluster_id"])
+
+	// Changing the rotation period only changes the expiry
+	config["rotation_days"] = 60
+	state = testApplyResource(t, res, state, config, client)
+	r.Equal(accessorID, state.ID)
+	r.Equal(createdAt.AddDate(0, 0, 60).Format(time.RFC3339), state.Attributes["expires_at"])
+
+	// Lowering the rotation period past the token's age keeps the token until the next refresh
+	aged := state.DeepCopy()
+	agedCreatedAt := time.Now().AddDate(0, 0, -10).UTC()
+	aged.Attributes["created_at"] = agedCreatedAt.Format(time.RFC3339)
+	aged.Attributes["expires_at"] = agedCreatedAt.AddDate(0, 0, 60).Format(time.RFC3339)
+	config["rotation_days"] = 5
+	updated := testApplyResource(t, res, aged, config, client)
+	r.Equal(accessorID, updated.ID)
+	r.Equal(agedCreatedAt.AddDate(0, 0, 5).Format(time.RFC3339), updated.Attributes["expires_at"])
+
+	newState, diags := res.RefreshWithoutUpgrade(context.Background(), updated, client)
+	r.False(diags.HasError())
+	r.Nil(newState)
+	config["rotation_days"] = 60
+
+	// Changing a keeper value rotates the token
+	config["rotate_when_changed"] = map[string]interface{}{"version": "2"}
+	state = testApplyResource(t, res, state, config, client)
+	r.NotEqual(accessorID, state.ID)
+
+	// An unexpired token is kept on refresh
+	state = testRefreshResource(t, res, state, client)
+	r.NotEmpty(state.ID)
+
+	// An expired token is removed from the state on refresh
+	expired := state.DeepCopy()
+	expired.Attributes["expires_at"] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	newState, diags = res.RefreshWithoutUpgrade(context.Background(), expired, client)
+	r.False(diags.HasError())
+	r.Nil(newState)
+
+	// A token of a cluster that has been re-created is removed from the state on refresh
+	recreated := state.DeepCopy()
+	recreated.Attributes["consul_cluster_id"] = "00000000-0000-0000-0000-000000000000"
+	newState, diags = res.RefreshWithoutUpgrade(context.Background(), recreated, client)
+	r.False(diags.HasError())
+	r.Nil(newState)
+}
+
+func TestResourceClusterRootToken_pgpKey(t *testing.T) {
+	r := require.New(t)
+
+	_, client := testFakeServer(t)
+	testCreateCluster(t, client)
+	res := resourceClusterRootToken()
+
+	entity, pgpKey := testPGPKey(t)
+	config := map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"pgp_key":                  pgpKey,
+	}
+
+	state := testApplyResource(t, res, nil, config, client)
+	r.Empty(state.Attributes["secret_id"])
+	r.Empty(state.Attributes["kubernetes_secret"])
+	r.Equal(fmt.Sprintf("%x", entity.PrimaryKey.Fingerprint), state.Attributes["key_fingerprint"])
+
+	// The encrypted secret ID is a UUID like the plaintext one
+	secretID := testPGPDecrypt(t, entity, state.Attributes["encrypted_secret_id"])
+	r.Len(secretID, 36)
+
+	// An invalid key is rejected at plan time
+	config["pgp_key"] = "dGVzdA=="
+	diags := res.Validate(terraform.NewResourceConfigRaw(config))
+	r.True(diags.HasError())
 }
 
-const testClusterRootToken = `
-resource "hcs_cluster_root_token" "example" {
-  managed_application_name = hcs_cluster.example.managed_application_name
-  resource_group = 			 hcs_cluster.example.resource_group
-}    
-`
+func TestResourceClusterRootToken_keybasePGPKey(t *testing.T) {
+	r := require.New(t)
+
+	_, client := testFakeServer(t)
+	testCreateCluster(t, client)
+	res := resourceClusterRootToken()
+
+	entity, pgpKey := testPGPKey(t)
+	config := map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"pgp_key":                  "keybase:test",
+		"keybase_public_keys": map[string]interface{}{
+			"test": pgpKey,
+		},
+	}
+
+	// The Keybase reference is resolved to the exported key of the user
+	state := testApplyResource(t, res, nil, config, client)
+	r.Equal(fmt.Sprintf("%x", entity.PrimaryKey.Fingerprint), state.Attributes["key_fingerprint"])
+	r.Len(testPGPDecrypt(t, entity, state.Attributes["encrypted_secret_id"]), 36)
+
+	// A Keybase reference without an exported key, or with an invalid one, is rejected at plan time
+	config["pgp_key"] = "keybase:other"
+	_, err := res.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), client)
+	r.EqualError(err, `invalid value for pgp_key: no public key for keybase user "other"; export it with `+
+		"`keybase pgp export -q other` and pass it in keybase_public_keys")
+
+	config["pgp_key"] = "keybase:test"
+	config["keybase_public_keys"] = map[string]interface{}{
+		"test": "dGVzdA==",
+	}
+	_, err = res.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), client)
+	r.Error(err)
+	r.Contains(err.Error(), "invalid value for pgp_key: invalid PGP public key for keybase:test")
+}
@@ -4,37 +4,586 @@
 package provider
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"regexp"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
 )
 
-func TestAccResourceScaffolding(t *testing.T) {
-	t.Skip("resource not yet implemented, remove this once you add your own code")
-
-	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: providerFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: testAccResourceScaffolding,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestMatchResourceAttr(
-						"scaffolding_resource.foo", "sample_attribute", regexp.MustCompile("^ba")),
-				),
-			},
-		},
-	})
+// testClusterConfig is the config of the HCS cluster created by testCreateCluster.
+var testClusterConfig = map[string]interface{}{
+	"resource_group_name":      testResourceGroupName,
+	"managed_application_name": "hcs-test",
+	"email":                    "test@example.com",
+	"cluster_mode":             "Production",
+	"min_consul_version":       "v1.9.4",
+	"tags": map[string]interface{}{
+		"env": "test",
+	},
+}
+
+// testCreateCluster creates the HCS cluster configured by testClusterConfig and returns its state.
+func testCreateCluster(t *testing.T, meta interface{}) *terraform.InstanceState {
+	return testApplyResource(t, resourceCluster(), nil, testClusterConfig, meta)
+}
+
+func TestResourceCluster_lifecycle(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	res := resourceCluster()
+
+	// Create
+	state := testCreateCluster(t, client)
+	managedAppID := state.ID
+	r.True(server.HasApplication(managedAppID))
+	r.Equal(managedAppID, state.Attributes["managed_application_id"])
+	r.Equal("hcs-test", state.Attributes["cluster_name"])
+	r.Equal("hcs-test", state.Attributes["consul_datacenter"])
+	r.Equal("westus2", state.Attributes["location"])
+	r.Equal("on-demand-v2", state.Attributes["plan_name"])
+	r.Equal("RUNNING", state.Attributes["state"])
+	r.Equal("v1.9.4", state.Attributes["consul_version"])
+	r.Equal("hcs-test-vnet", state.Attributes["vnet_name"])
+	r.Equal("test", state.Attributes["tags.env"])
+	r.Equal(server.RootTokenAccessorID(managedAppID), state.Attributes["consul_root_token_accessor_id"])
+	r.NotEmpty(state.Attributes["consul_root_token_secret_id"])
+	r.NotEmpty(state.Attributes["consul_config_file"])
+	r.NotEmpty(state.Attributes["consul_ca_file"])
+	r.Equal("1", state.Attributes["consul_retry_join.#"])
+	r.NotEmpty(state.Attributes["consul_retry_join.0"])
+	r.NotEmpty(state.Attributes["consul_gossip_encryption_key"])
+	r.Contains(state.Attributes["consul_ca_pem"], "BEGIN CERTIFICATE")
+	r.Equal("CN=Consul Agent CA hcs-test,O=HashiCorp Inc.", state.Attributes["consul_ca_certificate.0.subject"])
+	r.NotEmpty(state.Attributes["consul_ca_certificate.0.sha256_fingerprint"])
+
+	// Read
+	state = testRefreshResource(t, res, state, client)
+	r.Equal(managedAppID, state.ID)
+
+	// Update
+	config := map[string]interface{}{}
+	for k, v := range testClusterConfig {
+		config[k] = v
+	}
+	config["min_consul_version"] = "v1.9.5"
+	config["audit_logging_enabled"] = true
+	config["audit_log_storage_container_url"] = "https://example.blob.core.windows.net/audit"
+	config["tags"] = map[string]interface{}{"env": "prod"}
+
+	state = testApplyResource(t, res, state, config, client)
+	r.Equal(managedAppID, state.ID)
+	r.Equal("v1.9.5", state.Attributes["consul_version"])
+	r.Equal("1", state.Attributes["planned_upgrade_path.#"])
+	r.Equal("v1.9.5", state.Attributes["planned_upgrade_path.0"])
+	r.Equal("true", state.Attributes["audit_logging_enabled"])
+	r.Equal("https://example.blob.core.windows.net/audit", state.Attributes["audit_log_storage_container_url"])
+	r.Equal("prod", state.Attributes["tags.env"])
+
+	cluster, ok := server.Cluster(managedAppID)
+	r.True(ok)
+	r.Equal("v1.9.5", cluster.Properties.ConsulCurrentVersion)
+
+	// Import
+	imported := testImportResource(t, res, managedAppID+":hcs-test", client)
+	r.Equal(managedAppID, imported.ID)
+	for _, attr := range []string{"resource_group_name", "managed_application_name", "cluster_name", "consul_version", "vnet_id", "consul_cluster_id", "tags.env"} {
+		r.Equal(state.Attributes[attr], imported.Attributes[attr], attr)
+	}
+
+	// Delete
+	testDestroyResource(t, res, state, client)
+	r.False(server.HasApplication(managedAppID))
+
+	// A deleted cluster is removed from the state on refresh
+	newState, diags := res.RefreshWithoutUpgrade(context.Background(), state, client)
+	r.False(diags.HasError())
+	r.Nil(newState)
+}
+
+func TestResourceCluster_pgpKey(t *testing.T) {
+	r := require.New(t)
+
+	_, client := testFakeServer(t)
+
+	entity, pgpKey := testPGPKey(t)
+	config := map[string]interface{}{}
+	for k, v := range testClusterConfig {
+		config[k] = v
+	}
+	config["pgp_key"] = pgpKey
+
+	state := testApplyResource(t, resourceCluster(), nil, config, client)
+	r.Empty(state.Attributes["consul_root_token_secret_id"])
+	r.Equal(fmt.Sprintf("%x", entity.PrimaryKey.Fingerprint), state.Attributes["consul_root_token_key_fingerprint"])
+	r.Len(testPGPDecrypt(t, entity, state.Attributes["consul_root_token_encrypted_secret_id"]), 36)
+
+	// Changing the PGP key neither re-creates the cluster nor re-encrypts the root token
+	_, config["pgp_key"] = testPGPKey(t)
+	diff, err := resourceCluster().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	r.NoError(err)
+	r.False(diff.RequiresNew())
+
+	newState := testApplyResource(t, resourceCluster(), state, config, client)
+	r.Equal(state.ID, newState.ID)
+	r.Equal(state.Attributes["consul_root_token_encrypted_secret_id"], newState.Attributes["consul_root_token_encrypted_secret_id"])
+	r.Equal(state.Attributes["consul_root_token_key_fingerprint"], newState.Attributes["consul_root_token_key_fingerprint"])
+}
+
+func TestResourceCluster_purchaseCancellation(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	server.PurchaseCancellationRequests = 20
+	res := resourceCluster()
+
+	// Delete waits for the Managed Resource Group to be deleted, after which the cluster can be re-created
+	state := testCreateCluster(t, client)
+	mrgName := state.Attributes["managed_resource_group_name"]
+	testDestroyResource(t, res, state, client)
+	mrg, err := client.ResourceGroup.Get(context.Background(), mrgName)
+	r.Error(err)
+	r.Equal(404, mrg.StatusCode)
+
+	state = testCreateCluster(t, client)
+
+	// Create retries while the purchase of a cluster deleted outside of Terraform is being canceled
+	future, err := client.ManagedApplication.DeleteByID(context.Background(), state.ID)
+	r.NoError(err)
+	r.NoError(future.WaitForCompletionRef(context.Background(), client.ManagedApplication.Client))
+
+	_, err = client.ResourceGroup.Get(context.Background(), mrgName)
+	r.NoError(err)
+
+	state = testCreateCluster(t, client)
+	r.True(server.HasApplication(state.ID))
 }
 
-const testAccResourceScaffolding = `
-resource "scaffolding_resource" "foo" {
-  sample_attribute = "bar"
+func TestResourceCluster_multiHopUpgrade(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	res := resourceCluster()
+
+	config := map[string]interface{}{}
+	for k, v := range testClusterConfig {
+		config[k] = v
+	}
+	config["min_consul_version"] = "v1.8.10"
+
+	state := testApplyResource(t, res, nil, config, client)
+	r.Equal("v1.8.10", state.Attributes["consul_version"])
+
+	// v1.10.0 is two minor releases ahead, so the cluster is upgraded through v1.9.5
+	config["min_consul_version"] = "v1.10.0"
+	diff, err := res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	r.NoError(err)
+	r.Equal("2", diff.Attributes["planned_upgrade_path.#"].New)
+	r.Equal("v1.9.5", diff.Attributes["planned_upgrade_path.0"].New)
+	r.Equal("v1.10.0", diff.Attributes["planned_upgrade_path.1"].New)
+
+	state = testApplyResource(t, res, state, config, client)
+	r.Equal("v1.10.0", state.Attributes["consul_version"])
+	r.Equal("v1.10.0", state.Attributes["min_consul_version"])
+
+	cluster, ok := server.Cluster(state.ID)
+	r.True(ok)
+	r.Equal("v1.10.0", cluster.Properties.ConsulCurrentVersion)
+
+	// Without a version change no upgrade is planned
+	diff, err = res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	r.NoError(err)
+	r.Nil(diff)
+}
+
+func TestResourceCluster_multiHopUpgradeFailure(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	res := resourceCluster()
+
+	config := map[string]interface{}{}
+	for k, v := range testClusterConfig {
+		config[k] = v
+	}
+	config["min_consul_version"] = "v1.8.10"
+
+	state := testApplyResource(t, res, nil, config, client)
+
+	// The upgrade to v1.10.0 fails after the cluster has been upgraded to v1.9.5
+	server.FailingUpgradeVersions = []string{"v1.10.0"}
+	config["min_consul_version"] = "v1.10.0"
+	diff, err := res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	r.NoError(err)
+
+	state, diags := res.Apply(context.Background(), state, diff, client)
+	r.True(diags.HasError())
+	r.Equal("v1.9.5", state.Attributes["consul_version"])
+	r.Equal("v1.9.5", state.Attributes["min_consul_version"])
+	r.Equal("1", state.Attributes["planned_upgrade_path.#"])
+	r.Equal("v1.10.0", state.Attributes["planned_upgrade_path.0"])
+
+	cluster, ok := server.Cluster(state.ID)
+	r.True(ok)
+	r.Equal("v1.9.5", cluster.Properties.ConsulCurrentVersion)
+
+	// The next apply upgrades the cluster through the remaining versions
+	server.FailingUpgradeVersions = nil
+	diff, err = res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	r.NoError(err)
+	r.NotNil(diff)
+	r.Equal("v1.10.0", diff.Attributes["min_consul_version"].New)
+
+	state = testApplyResource(t, res, state, config, client)
+	r.Equal("v1.10.0", state.Attributes["consul_version"])
+	r.Equal("1", state.Attributes["planned_upgrade_path.#"])
+	r.Equal("v1.10.0", state.Attributes["planned_upgrade_path.0"])
+}
+
+func TestResourceCluster_federationDeleteWait(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	res := resourceCluster()
+
+	primaryConfig := map[string]interface{}{}
+	for k, v := range testClusterConfig {
+		primaryConfig[k] = v
+	}
+	primaryConfig["federation_delete_wait"] = "1m"
+	primaryState := testApplyResource(t, res, nil, primaryConfig, client)
+
+	tokenState := testReadDataSource(t, dataSourceFederationToken(), map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+	}, client)
+
+	secondaryConfig := map[string]interface{}{}
+	for k, v := range testClusterConfig {
+		secondaryConfig[k] = v
+	}
+	secondaryConfig["managed_application_name"] = "hcs-secondary"
+	secondaryConfig["consul_federation_token"] = tokenState.Attributes["token"]
+	secondaryState := testApplyResource(t, res, nil, secondaryConfig, client)
+
+	// Without waiting the primary cannot be deleted while the secondary exists
+	noWaitState := primaryState.DeepCopy()
+	delete(noWaitState.Attributes, "federation_delete_wait")
+	_, diags := res.Apply(context.Background(), noWaitState, &terraform.InstanceDiff{Destroy: true}, client)
+	r.True(diags.HasError())
+	r.Contains(diags[0].Summary, "unable to delete primary datacenter of a federation")
+
+	// The wait times out if the secondary is not deleted
+	shortWaitState := primaryState.DeepCopy()
+	shortWaitState.Attributes["federation_delete_wait"] = "5ms"
+	_, diags = res.Apply(context.Background(), shortWaitState, &terraform.InstanceDiff{Destroy: true}, client)
+	r.True(diags.HasError())
+	r.Contains(diags[0].Summary, "timed out after 5ms waiting for the secondary datacenters")
+	r.Contains(diags[0].Summary, testResourceGroupName+"/hcs-secondary")
+	r.True(server.HasApplication(primaryState.ID))
+
+	// The primary is deleted once the secondary, which is deleted in parallel, is gone
+	primaryDeleted := make(chan error)
+	go func() {
+		_, diags := res.Apply(context.Background(), primaryState, &terraform.InstanceDiff{Destroy: true}, client)
+		primaryDeleted <- helper.ToError(diags).ErrorOrNil()
+	}()
+
+	testDestroyResource(t, res, secondaryState, client)
+	r.NoError(<-primaryDeleted)
+	r.False(server.HasApplication(primaryState.ID))
+}
+
+func TestResourceCluster_federationTokenWriteOnly(t *testing.T) {
+	r := require.New(t)
+
+	server, client := testFakeServer(t)
+	res := resourceCluster()
+
+	testCreateCluster(t, client)
+
+	tokenState := testReadDataSource(t, dataSourceFederationToken(), map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+	}, client)
+	token := tokenState.Attributes["token"]
+
+	secondaryConfig := map[string]interface{}{}
+	for k, v := range testClusterConfig {
+		secondaryConfig[k] = v
+	}
+	secondaryConfig["managed_application_name"] = "hcs-secondary"
+
+	// The cluster joins the federation, but the federation token is not stored in the state
+	state := testApplyResourceWriteOnly(t, res, nil, secondaryConfig, map[string]string{
+		"consul_federation_token_wo": token,
+	}, client)
+	r.Empty(state.Attributes["consul_federation_token"])
+	r.NotContains(state.Attributes, "consul_federation_token_wo")
+
+	cluster, ok := server.Cluster(state.ID)
+	r.True(ok)
+	r.Equal(token, cluster.Properties.FederationToken)
+
+	// The federation token is not read into the state on refresh
+	state = testRefreshResource(t, res, state, client)
+	r.Empty(state.Attributes["consul_federation_token"])
+
+	diff, err := res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(secondaryConfig), client)
+	r.NoError(err)
+	if diff != nil {
+		r.NotContains(diff.Attributes, "consul_federation_token")
+		r.False(diff.RequiresNew())
+	}
+
+	// An imported cluster has the federation token in the state
+	imported := testImportResource(t, res, state.ID+":"+state.Attributes["cluster_name"], client)
+	r.Equal(token, imported.Attributes["consul_federation_token"])
+
+	// which does not force recreation if the federation token is passed to consul_federation_token_wo
+	diff, err = res.Diff(context.Background(), imported, testResourceConfigWriteOnly(res, secondaryConfig, map[string]string{
+		"consul_federation_token_wo": token,
+	}), client)
+	r.NoError(err)
+	if diff != nil {
+		r.False(diff.RequiresNew())
+	}
+
+	diff, err = res.Diff(context.Background(), imported, terraform.NewResourceConfigRaw(secondaryConfig), client)
+	r.NoError(err)
+	r.True(diff.RequiresNew())
+}
+
+func TestResourceCluster_customizeDiff(t *testing.T) {
+	server, client := testFakeServer(t)
+	server.AddResourceGroup("unsupported-rg", "brazilsouth")
+	res := resourceCluster()
+
+	state := testCreateCluster(t, client)
+
+	olderConfig := map[string]interface{}{"managed_application_name": "hcs-test-older", "min_consul_version": "v1.8.10"}
+	for k, v := range testClusterConfig {
+		if _, ok := olderConfig[k]; !ok {
+			olderConfig[k] = v
+		}
+	}
+	olderState := testApplyResource(t, res, nil, olderConfig, client)
+
+	missingState := state.DeepCopy()
+	missingState.ID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/" + testResourceGroupName + "/providers/Microsoft.Solutions/applications/missing"
+
+	// unreachableClient can't reach the HCP Consul versions endpoint
+	unreachableClient := *client
+	unreachableClient.Config.HCPApiDomain = "http://127.0.0.1:1"
+
+	// failingClient can't fetch Managed Applications
+	failingClient := *client
+	failingManagedAppClient := *client.ManagedApplication
+	failingManagedAppClient.BaseURI = "http://127.0.0.1:1"
+	failingManagedAppClient.RetryAttempts = 0
+	failingManagedAppClient.RetryDuration = 0
+	failingClient.ManagedApplication = &failingManagedAppClient
+
+	// failingResourceGroupClient can't fetch Resource Groups
+	failingResourceGroupClient := *client
+	failingGroupsClient := *client.ResourceGroup
+	failingGroupsClient.BaseURI = "http://127.0.0.1:1"
+	failingGroupsClient.RetryAttempts = 0
+	failingGroupsClient.RetryDuration = 0
+	failingResourceGroupClient.ResourceGroup = &failingGroupsClient
+
+	tcs := map[string]struct {
+		state     *terraform.InstanceState
+		config    map[string]interface{}
+		meta      interface{}
+		computed  []string
+		err       string
+		attribute string
+	}{
+		"valid create": {
+			config: map[string]interface{}{},
+		},
+		"unsupported location": {
+			config:    map[string]interface{}{"location": "Brazil South"},
+			err:       "invalid value for location: unsupported location: brazilsouth",
+			attribute: "location",
+		},
+		"unsupported resource group location": {
+			config:    map[string]interface{}{"resource_group_name": "unsupported-rg"},
+			err:       "invalid value for location: unsupported location: brazilsouth",
+			attribute: "location",
+		},
+		"missing resource group": {
+			config: map[string]interface{}{"resource_group_name": "missing-rg"},
+		},
+		"failing resource group fetch": {
+			config: map[string]interface{}{"resource_group_name": "unsupported-rg"},
+			meta:   &failingResourceGroupClient,
+		},
+		"unsupported location and audit logging without storage container": {
+			config:    map[string]interface{}{"location": "Brazil South", "audit_logging_enabled": true},
+			err:       "invalid value for location: unsupported location: brazilsouth",
+			attribute: "location",
+		},
+		"unavailable consul version": {
+			config:    map[string]interface{}{"min_consul_version": "v1.7.0"},
+			err:       "invalid value for min_consul_version: specified Consul version (v1.7.0) is unavailable",
+			attribute: "min_consul_version",
+		},
+		"unreachable HCP API on create": {
+			config: map[string]interface{}{"min_consul_version": "v1.7.0"},
+			meta:   &unreachableClient,
+		},
+		"audit logging without storage container": {
+			config:    map[string]interface{}{"audit_logging_enabled": true},
+			err:       "invalid value for audit_log_storage_container_url: must be set when audit_logging_enabled is true",
+			attribute: "audit_log_storage_container_url",
+		},
+		"valid upgrade": {
+			state:  state,
+			config: map[string]interface{}{"min_consul_version": "v1.9.5"},
+		},
+		"invalid upgrade": {
+			state:     state,
+			config:    map[string]interface{}{"min_consul_version": "v1.11.0"},
+			err:       "invalid value for min_consul_version: specified Consul version (v1.11.0) is unavailable",
+			attribute: "min_consul_version",
+		},
+		"unreachable HCP API on multi-hop upgrade": {
+			state:    olderState,
+			config:   map[string]interface{}{"managed_application_name": "hcs-test-older", "min_consul_version": "v1.10.0"},
+			meta:     &unreachableClient,
+			computed: []string{"planned_upgrade_path.#"},
+		},
+		"upgrade of missing cluster": {
+			state:    missingState,
+			config:   map[string]interface{}{"min_consul_version": "v1.9.5"},
+			computed: []string{"planned_upgrade_path.#"},
+		},
+		"failing cluster fetch on upgrade": {
+			state:  state,
+			config: map[string]interface{}{"min_consul_version": "v1.9.5"},
+			meta:   &failingClient,
+			err:    "unable to fetch HCS cluster (Managed Application ID \"" + state.ID + "\")",
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			config := map[string]interface{}{}
+			for k, v := range testClusterConfig {
+				config[k] = v
+			}
+			for k, v := range tc.config {
+				config[k] = v
+			}
+
+			meta := tc.meta
+			if meta == nil {
+				meta = client
+			}
+
+			diff, err := res.Diff(context.Background(), tc.state, terraform.NewResourceConfigRaw(config), meta)
+			if tc.err == "" {
+				r.NoError(err)
+				for _, attr := range tc.computed {
+					r.True(diff.Attributes[attr].NewComputed, attr)
+				}
+				return
+			}
+
+			r.Error(err)
+			r.Contains(err.Error(), tc.err)
+
+			var pathErr cty.PathError
+			if tc.attribute == "" {
+				r.False(errors.As(err, &pathErr))
+				return
+			}
+			r.True(errors.As(err, &pathErr))
+			r.Equal(cty.GetAttrPath(tc.attribute), pathErr.Path)
+		})
+	}
+}
+
+func Test_minConsulVersionDiffSuppress(t *testing.T) {
+	tcs := map[string]struct {
+		old      string
+		new      string
+		expected bool
+	}{
+		"not specified": {
+			old:      "v1.9.5",
+			new:      "",
+			expected: true,
+		},
+		"on creation": {
+			old:      "",
+			new:      "v1.9.5",
+			expected: false,
+		},
+		"less than the actual version": {
+			old:      "v1.9.5",
+			new:      "1.9.4",
+			expected: true,
+		},
+		"equal to the actual version with build metadata": {
+			old:      "v1.9.5",
+			new:      "v1.9.5+ent",
+			expected: true,
+		},
+		"greater than the actual version": {
+			old:      "v1.9.5",
+			new:      "v1.10.0",
+			expected: false,
+		},
+		"invalid version": {
+			old:      "v1.9.5",
+			new:      "latest",
+			expected: false,
+		},
+	}
+
+	diffSuppress := resourceCluster().Schema["min_consul_version"].DiffSuppressFunc
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(tc.expected, diffSuppress("min_consul_version", tc.old, tc.new, nil))
+		})
+	}
+}
+
+func Test_setClusterConsulConfigData_malformedCA(t *testing.T) {
+	r := require.New(t)
+
+	d := schema.TestResourceDataRaw(t, resourceCluster().Schema, map[string]interface{}{})
+	r.NoError(d.Set("consul_ca_certificate", []interface{}{
+		map[string]interface{}{
+			"subject": "CN=Consul Agent CA hcs-test,O=HashiCorp Inc.",
+		},
+	}))
+
+	encodedConfig := base64.StdEncoding.EncodeToString([]byte(`{"encrypt":"gossip-key","retry_join":["dc1.private.consul.example.com"]}`))
+	encodedCAFile := base64.StdEncoding.EncodeToString([]byte("not a certificate"))
+
+	r.NoError(setClusterConsulConfigData(d, encodedConfig, encodedCAFile))
+	r.Equal([]interface{}{"dc1.private.consul.example.com"}, d.Get("consul_retry_join"))
+	r.Equal("gossip-key", d.Get("consul_gossip_encryption_key"))
+	r.Equal("not a certificate", d.Get("consul_ca_pem"))
+	r.Empty(d.Get("consul_ca_certificate"))
 }
-`
 
 func Test_validateClusterImportString(t *testing.T) {
 	tcs := []struct {
@@ -128,24 +128,22 @@ func resourceSnapshotCreate(ctx context.Context, d *schema.ResourceData, meta in
 	resp, err := crpClient.CreateSnapshot(ctx, managedAppManagedResourceGroupID,
 		resourceGroupName, snapshotName)
 	if err != nil {
-		return diag.Errorf("unable to create snapshot (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to create snapshot (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
 			managedAppName,
 			resourceGroupName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
 	d.SetId(resp.SnapshotID)
 
-	err = crpClient.PollOperation(ctx, resp.Operation.ID, managedAppManagedResourceGroupID, managedAppName, 10)
+	err = crpClient.PollOperation(ctx, resp.Operation.ID, managedAppManagedResourceGroupID, managedAppName)
 
 	if err != nil {
-		return diag.Errorf("unable to poll create snapshot operation (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to poll create snapshot operation (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
 			managedAppName,
 			resourceGroupName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
@@ -194,11 +192,10 @@ func resourceSnapshotRead(ctx context.Context, d *schema.ResourceData, meta inte
 			return nil
 		}
 
-		return diag.Errorf("unable to fetch snapshot (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to fetch snapshot (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
 			managedAppName,
 			resourceGroupName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
@@ -242,11 +239,10 @@ func resourceSnapshotUpdate(ctx context.Context, d *schema.ResourceData, meta in
 	crpClient := meta.(*clients.Client).CustomResourceProvider
 	resp, err := crpClient.RenameSnapshot(ctx, managedResourceGroupID, resourceGroupName, snapshotID, snapshotName)
 	if err != nil {
-		return diag.Errorf("unable to rename snapshot (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to rename snapshot (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
 			managedAppName,
 			resourceGroupName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
@@ -289,21 +285,19 @@ func resourceSnapshotDelete(ctx context.Context, d *schema.ResourceData, meta in
 	resp, err := crpClient.DeleteSnapshot(ctx, managedAppManagedResourceGroupID,
 		resourceGroupName, snapshotID)
 	if err != nil {
-		return diag.Errorf("unable to delete snapshot (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to delete snapshot (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
 			managedAppName,
 			resourceGroupName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
-	err = crpClient.PollOperation(ctx, resp.Operation.ID, managedAppManagedResourceGroupID, managedAppName, 10)
+	err = crpClient.PollOperation(ctx, resp.Operation.ID, managedAppManagedResourceGroupID, managedAppName)
 	if err != nil {
-		return diag.Errorf("unable to poll delete snapshot operation (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+		return helper.ErrorDiagnostics(err, "unable to poll delete snapshot operation (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
 			managedAppName,
 			resourceGroupName,
 			meta.(*clients.Client).CorrelationRequestID,
-			err,
 		)
 	}
 
@@ -0,0 +1,218 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-hcs/internal/clients"
+	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
+)
+
+// snapshotRestoreTimeoutDuration is the amount of time that can elapse
+// before a snapshot restore operation should timeout.
+var snapshotRestoreTimeoutDuration = time.Minute * 30
+
+// resourceSnapshotRestore defines the snapshot restore resource schema and CRUD contexts.
+func resourceSnapshotRestore() *schema.Resource {
+	return &schema.Resource{
+		Description: "The snapshot restore resource restores a Consul snapshot into an HCS cluster." +
+			" The restore is performed when the resource is created, and performed again whenever one of its arguments changes." +
+			" Destroying the resource only removes it from the Terraform state.",
+		CreateContext: resourceSnapshotRestoreCreate,
+		ReadContext:   resourceSnapshotRestoreRead,
+		DeleteContext: resourceSnapshotRestoreDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Default: &defaultSnapshotTimeoutDuration,
+			Create:  &snapshotRestoreTimeoutDuration,
+		},
+		Schema: map[string]*schema.Schema{
+			// Required inputs
+			"resource_group_name": {
+				Description:      "The name of the Resource Group in which the HCS Azure Managed Application belongs.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateResourceGroupName,
+				ForceNew:         true,
+			},
+			"managed_application_name": {
+				Description:      "The name of the HCS Azure Managed Application.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateManagedAppName,
+				ForceNew:         true,
+			},
+			"snapshot_id": {
+				Description:      "The ID of the snapshot to restore.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateStringNotEmpty,
+				ForceNew:         true,
+			},
+			// Optional inputs
+			"take_snapshot": {
+				Description: "Denotes that a snapshot of the cluster should be taken before the restore is performed.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"triggers": {
+				Description: "A map of arbitrary values that, when changed, will cause the snapshot to be restored again.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			// Computed outputs
+			"operation_id": {
+				Description: "The ID of the restore operation.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"snapshot_name": {
+				Description: "The name of the restored snapshot.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"restored_at": {
+				Description: "Timestamp of when the snapshot was restored.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceSnapshotRestoreCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceGroupName := d.Get("resource_group_name").(string)
+	managedAppName := d.Get("managed_application_name").(string)
+
+	managedAppClient := meta.(*clients.Client).ManagedApplication
+	app, err := managedAppClient.Get(ctx, resourceGroupName, managedAppName)
+	if err != nil {
+		if helper.IsAutoRestResponseCodeNotFound(app.Response) {
+			// No managed application exists, so we should not try to restore the snapshot
+			return diag.Errorf("unable to restore snapshot; HCS cluster not found (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+				managedAppName,
+				resourceGroupName,
+				meta.(*clients.Client).CorrelationRequestID,
+			)
+		}
+
+		return diag.Errorf("unable to check for presence of an existing HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+			err,
+		)
+	}
+
+	managedAppManagedResourceGroupID := *app.ManagedResourceGroupID
+	snapshotID := d.Get("snapshot_id").(string)
+
+	crpClient := meta.(*clients.Client).CustomResourceProvider
+	resp, err := crpClient.RestoreSnapshot(ctx, managedAppManagedResourceGroupID,
+		resourceGroupName, snapshotID, d.Get("take_snapshot").(bool))
+	if err != nil {
+		return helper.ErrorDiagnostics(err, "unable to restore snapshot (Snapshot ID %q) (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			snapshotID,
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	d.SetId(resp.Operation.ID)
+
+	if err := d.Set("operation_id", resp.Operation.ID); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = crpClient.PollOperation(ctx, resp.Operation.ID, managedAppManagedResourceGroupID, managedAppName)
+	if err != nil {
+		return helper.ErrorDiagnostics(err, "unable to poll restore snapshot operation (Snapshot ID %q) (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			snapshotID,
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	return resourceSnapshotRestoreRead(ctx, d, meta)
+}
+
+func resourceSnapshotRestoreRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceGroupName := d.Get("resource_group_name").(string)
+	managedAppName := d.Get("managed_application_name").(string)
+
+	managedAppClient := meta.(*clients.Client).ManagedApplication
+	app, err := managedAppClient.Get(ctx, resourceGroupName, managedAppName)
+	if err != nil {
+		if helper.IsAutoRestResponseCodeNotFound(app.Response) {
+			// No managed application exists, so this restore should be removed from state
+			log.Printf("[WARN] no HCS Cluster found for (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+				managedAppName,
+				resourceGroupName,
+				meta.(*clients.Client).CorrelationRequestID,
+			)
+			d.SetId("")
+			return nil
+		}
+
+		return diag.Errorf("unable to check for presence of an existing HCS cluster (Managed Application %q) (Resource Group %q) (Correlation ID %q): %v",
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+			err,
+		)
+	}
+
+	snapshotID := d.Get("snapshot_id").(string)
+
+	crpClient := meta.(*clients.Client).CustomResourceProvider
+	resp, err := crpClient.GetSnapshot(ctx, *app.ManagedResourceGroupID, resourceGroupName, snapshotID)
+	if err != nil {
+		if crpClient.IsCRPErrorAzureNotFound(err) {
+			// The restore has already happened, so a snapshot removed by the retention
+			// policy should not cause the restore to be performed again.
+			log.Printf("[WARN] restored snapshot (Snapshot ID %q) no longer exists; the retention policy for snapshots is 30 days", snapshotID)
+			return nil
+		}
+
+		return helper.ErrorDiagnostics(err, "unable to fetch restored snapshot (Snapshot ID %q) (Managed Application %q) (Resource Group %q) (Correlation ID %q)",
+			snapshotID,
+			managedAppName,
+			resourceGroupName,
+			meta.(*clients.Client).CorrelationRequestID,
+		)
+	}
+
+	if err := d.Set("snapshot_name", resp.Snapshot.Name); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if resp.Snapshot.RestoredAt.String() != defaultRestoredAt {
+		if err := d.Set("restored_at", resp.Snapshot.RestoredAt.String()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+// resourceSnapshotRestoreDelete is a no-op as a restore cannot be undone; the
+// resource is only removed from the Terraform state.
+func resourceSnapshotRestoreDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO] removing snapshot restore (Operation ID %q) from state; the cluster data is not modified", d.Id())
+
+	return nil
+}
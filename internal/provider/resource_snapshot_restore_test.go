@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceSnapshotRestore_lifecycle(t *testing.T) {
+	r := require.New(t)
+
+	_, client := testFakeServer(t)
+	testCreateCluster(t, client)
+	res := resourceSnapshotRestore()
+
+	snapshotState := testApplyResource(t, resourceSnapshot(), nil, map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"snapshot_name":            "snapshot-name",
+	}, client)
+
+	config := map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"snapshot_id":              snapshotState.ID,
+		"triggers": map[string]interface{}{
+			"restore": "1",
+		},
+	}
+
+	// Create, which polls the restore operation until it is done
+	state := testApplyResource(t, res, nil, config, client)
+	operationID := state.ID
+	r.NotEmpty(operationID)
+	r.Equal(operationID, state.Attributes["operation_id"])
+	r.Equal("snapshot-name", state.Attributes["snapshot_name"])
+	r.NotEmpty(state.Attributes["restored_at"])
+
+	// Read
+	state = testRefreshResource(t, res, state, client)
+	r.Equal(operationID, state.ID)
+	r.Equal("snapshot-name", state.Attributes["snapshot_name"])
+
+	// Changing the triggers restores the snapshot again
+	config["triggers"] = map[string]interface{}{
+		"restore": "2",
+	}
+	diff, err := res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	r.NoError(err)
+	r.True(diff.RequiresNew())
+
+	testDestroyResource(t, res, state, client)
+	state = testApplyResource(t, res, nil, config, client)
+	r.NotEqual(operationID, state.ID)
+	r.Equal(state.ID, state.Attributes["operation_id"])
+	r.NotEmpty(state.Attributes["restored_at"])
+
+	// Taking a snapshot before the restore restores the snapshot again and keeps a completed
+	// snapshot of the cluster from before the restore
+	config["take_snapshot"] = true
+	diff, err = res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	r.NoError(err)
+	r.True(diff.RequiresNew())
+
+	testDestroyResource(t, res, state, client)
+	state = testApplyResource(t, res, nil, config, client)
+	r.Equal("true", state.Attributes["take_snapshot"])
+
+	snapshots := testReadDataSource(t, dataSourceSnapshots(), map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"name_regex":               "^pre-restore-",
+	}, client)
+	r.Equal("1", snapshots.Attributes["snapshots.#"])
+	r.Equal("pre-restore-snapshot-name", snapshots.Attributes["snapshots.0.name"])
+	r.Equal("COMPLETED", snapshots.Attributes["snapshots.0.state"])
+
+	// Delete only removes the restore from the state; the restored snapshot is kept
+	testDestroyResource(t, res, state, client)
+	snapshotState = testRefreshResource(t, resourceSnapshot(), snapshotState, client)
+	r.NotNil(snapshotState)
+	r.Equal("COMPLETED", snapshotState.Attributes["state"])
+
+	// Restoring a snapshot that does not exist fails
+	config["snapshot_id"] = "00000000-0000-0000-0000-000000000000"
+	diff, err = res.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), client)
+	r.NoError(err)
+	_, diags := res.Apply(context.Background(), nil, diff, client)
+	r.True(diags.HasError())
+	r.Contains(diags[0].Summary, "unable to restore snapshot")
+}
@@ -4,34 +4,50 @@
 package provider
 
 import (
-	"regexp"
+	"context"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
 )
 
-func TestSnapshotResourceScaffolding(t *testing.T) {
-	t.Skip("resource not yet implemented, remove this once you add your own code")
-
-	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: providerFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: testSnapshotResource,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestMatchResourceAttr(
-						"scaffolding_resource.foo", "sample_attribute", regexp.MustCompile("^ba")),
-				),
-			},
-		},
-	})
-}
+func TestResourceSnapshot_lifecycle(t *testing.T) {
+	r := require.New(t)
+
+	_, client := testFakeServer(t)
+	testCreateCluster(t, client)
+	res := resourceSnapshot()
+
+	config := map[string]interface{}{
+		"resource_group_name":      testResourceGroupName,
+		"managed_application_name": "hcs-test",
+		"snapshot_name":            "snapshot-name",
+	}
+
+	// Create
+	state := testApplyResource(t, res, nil, config, client)
+	snapshotID := state.ID
+	r.NotEmpty(snapshotID)
+	r.Equal("snapshot-name", state.Attributes["snapshot_name"])
+	r.Equal("COMPLETED", state.Attributes["state"])
+	r.Equal("16384", state.Attributes["size"])
+	r.NotEmpty(state.Attributes["requested_at"])
+	r.NotEmpty(state.Attributes["finished_at"])
+
+	// Read
+	state = testRefreshResource(t, res, state, client)
+	r.Equal(snapshotID, state.ID)
+
+	// Update
+	config["snapshot_name"] = "renamed-snapshot"
+	state = testApplyResource(t, res, state, config, client)
+	r.Equal(snapshotID, state.ID)
+	r.Equal("renamed-snapshot", state.Attributes["snapshot_name"])
+
+	// Delete
+	testDestroyResource(t, res, state, client)
 
-const testSnapshotResource = `
-resource "hcs_snapshot" "example" {
-  resource_group_name = "mrg-name"
-  managed_application_name = "managed-app-name"
-  snapshot_name = "snapshot-name"
+	// A deleted snapshot is removed from the state on refresh
+	newState, diags := res.RefreshWithoutUpgrade(context.Background(), state, client)
+	r.False(diags.HasError())
+	r.Nil(newState)
 }
-`
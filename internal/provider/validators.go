@@ -6,13 +6,17 @@ package provider
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
 
 	"github.com/hashicorp/terraform-provider-hcs/internal/helper"
 )
@@ -253,3 +257,268 @@ func validateAzureTags(v interface{}, path cty.Path) diag.Diagnostics {
 
 	return diagnostics
 }
+
+// validateRegexp ensures that the provided string is a valid regular expression.
+func validateRegexp(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if _, err := regexp.Compile(v.(string)); err != nil {
+		msg := "must be a valid regular expression"
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        err.Error(),
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validateRFC3339 ensures that the provided string is a valid RFC 3339 timestamp.
+func validateRFC3339(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if _, err := time.Parse(time.RFC3339, v.(string)); err != nil {
+		msg := "must be a valid RFC 3339 timestamp"
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        err.Error(),
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validateBillingMonth ensures that the provided string is a month of the format YYYY/MM.
+func validateBillingMonth(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if !regexp.MustCompile(`^\d{4}/(0[1-9]|1[0-2])$`).MatchString(v.(string)) {
+		msg := "must be a month of the format YYYY/MM"
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        msg,
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validateVersionConstraint ensures that the provided string is a version constraint expression,
+// e.g. `~> 1.9` or `>= 1.8, < 1.10`.
+func validateVersionConstraint(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if _, err := version.NewConstraint(v.(string)); err != nil {
+		msg := "must be a valid version constraint"
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        err.Error(),
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validateDuration ensures that the provided string is a positive duration, e.g. `30m` or `1h30m`.
+func validateDuration(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	duration, err := time.ParseDuration(v.(string))
+	if err != nil || duration <= 0 {
+		msg := "must be a positive duration, e.g. 30m or 1h30m"
+		detail := msg
+		if err != nil {
+			detail = err.Error()
+		}
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        detail,
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validateIntAtLeast returns a func which ensures the int value is at least min.
+func validateIntAtLeast(min int) schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		var diagnostics diag.Diagnostics
+
+		if value := v.(int); value < min {
+			msg := fmt.Sprintf("expected %d to be at least %d", value, min)
+			diagnostics = append(diagnostics, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       msg,
+				Detail:        msg,
+				AttributePath: path,
+			})
+		}
+
+		return diagnostics
+	}
+}
+
+// validatePGPKey ensures that the provided string is a base64 encoded or ASCII armored PGP public key,
+// or a Keybase reference (keybase:username). Keybase references are resolved during plan, since the
+// exported key is passed in another attribute.
+func validatePGPKey(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if username, ok := helper.KeybaseUsername(v.(string)); ok {
+		if username == "" {
+			diagnostics = append(diagnostics, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "must be a Keybase reference with a username, e.g. keybase:username",
+				Detail:        "must be a Keybase reference with a username, e.g. keybase:username",
+				AttributePath: path,
+			})
+		}
+
+		return diagnostics
+	}
+
+	if _, err := helper.ParsePGPKey(v.(string)); err != nil {
+		msg := "must be a base64 encoded or ASCII armored PGP public key"
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        err.Error(),
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validateAbsolutePath ensures that the provided string is an absolute Unix path, e.g. `/etc/consul.d`.
+func validateAbsolutePath(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if value := v.(string); !strings.HasPrefix(value, "/") || strings.ContainsAny(value, "\n\r") {
+		msg := "must be an absolute path"
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        msg,
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validateURL ensures that the provided string is an absolute HTTP or HTTPS URL
+// without characters that would need to be escaped in a shell command.
+func validateURL(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	value := v.(string)
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.ContainsAny(value, " \t\n\r\"'`$\\") {
+		msg := "must be an absolute http or https URL"
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        msg,
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validatePackageVersion ensures that the provided string is a Debian package version, e.g. `1.9.5` or `1.9.5-1`.
+func validatePackageVersion(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if !regexp.MustCompile(`^[0-9][A-Za-z0-9.+~:-]*$`).MatchString(v.(string)) {
+		msg := "must be a package version, e.g. 1.9.5"
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        msg,
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validateHelmChartVersion ensures that the provided string is a supported consul-k8s Helm chart version.
+func validateHelmChartVersion(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	constraints, err := version.NewConstraint(supportedHelmChartVersions)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	chartVersion, err := version.NewVersion(v.(string))
+	if err != nil || !constraints.Check(chartVersion) {
+		msg := fmt.Sprintf("must be a consul-k8s Helm chart version matching %q", supportedHelmChartVersions)
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        msg,
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validateYAMLMap ensures that the provided string is a YAML document whose root is a map.
+func validateYAMLMap(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	var value map[string]interface{}
+	if err := yaml.Unmarshal([]byte(v.(string)), &value); err != nil {
+		msg := "must be a YAML map"
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        err.Error(),
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
+
+// validateHostPort ensures that the provided string is a host name or IP address, with an optional port,
+// e.g. `example.com`, `10.0.0.4:6443` or `[::1]:6443`.
+func validateHostPort(v interface{}, path cty.Path) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	host := v.(string)
+	validPort := true
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		host = h
+		_, err = strconv.ParseUint(port, 10, 16)
+		validPort = err == nil
+	}
+	host = strings.Trim(host, "[]")
+
+	validHost := host != "" && (net.ParseIP(host) != nil || !strings.ContainsAny(host, "/:@ \t\r\n"))
+	if !validHost || !validPort {
+		msg := "must be a host name or IP address with an optional port, without a scheme"
+		diagnostics = append(diagnostics, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       msg,
+			Detail:        msg,
+			AttributePath: path,
+		})
+	}
+
+	return diagnostics
+}
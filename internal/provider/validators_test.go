@@ -274,7 +274,7 @@ func Test_validateSemVer(t *testing.T) {
 				diag.Diagnostic{
 					Severity:      diag.Error,
 					Summary:       "must be a valid semver",
-					Detail:        "Malformed version: v1.2.foo",
+					Detail:        "malformed version: v1.2.foo",
 					AttributePath: nil,
 				},
 			},
@@ -386,3 +386,468 @@ func Test_validateAzureTags(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateRegexp(t *testing.T) {
+	tcs := map[string]struct {
+		expectErr bool
+		input     string
+	}{
+		"valid regexp": {
+			input:     "^nightly-.*$",
+			expectErr: false,
+		},
+		"invalid regexp": {
+			input:     "nightly-(",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateRegexp(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validateRFC3339(t *testing.T) {
+	tcs := map[string]struct {
+		expectErr bool
+		input     string
+	}{
+		"valid timestamp": {
+			input:     "2021-06-01T12:00:00Z",
+			expectErr: false,
+		},
+		"valid timestamp with offset": {
+			input:     "2021-06-01T12:00:00+02:00",
+			expectErr: false,
+		},
+		"date only": {
+			input:     "2021-06-01",
+			expectErr: true,
+		},
+		"empty string": {
+			input:     "",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateRFC3339(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validateBillingMonth(t *testing.T) {
+	tcs := map[string]struct {
+		expected diag.Diagnostics
+		input    string
+	}{
+		"valid month": {
+			input:    "2021/06",
+			expected: nil,
+		},
+		"invalid month": {
+			input: "2021/13",
+			expected: diag.Diagnostics{
+				diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "must be a month of the format YYYY/MM",
+					Detail:        "must be a month of the format YYYY/MM",
+					AttributePath: nil,
+				},
+			},
+		},
+		"dash separator": {
+			input: "2021-06",
+			expected: diag.Diagnostics{
+				diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "must be a month of the format YYYY/MM",
+					Detail:        "must be a month of the format YYYY/MM",
+					AttributePath: nil,
+				},
+			},
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateBillingMonth(tc.input, nil)
+			r.Equal(tc.expected, result)
+		})
+	}
+}
+
+func Test_validateVersionConstraint(t *testing.T) {
+	tcs := map[string]struct {
+		input     string
+		expectErr bool
+	}{
+		"pessimistic constraint": {
+			input:     "~> 1.9",
+			expectErr: false,
+		},
+		"range": {
+			input:     ">= 1.8, < 1.10",
+			expectErr: false,
+		},
+		"with a prefixed v": {
+			input:     "= v1.9.5",
+			expectErr: false,
+		},
+		"invalid operator": {
+			input:     "=> 1.9",
+			expectErr: true,
+		},
+		"empty string": {
+			input:     "",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateVersionConstraint(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validateDuration(t *testing.T) {
+	tcs := map[string]struct {
+		expectErr bool
+		input     string
+	}{
+		"minutes": {
+			input:     "30m",
+			expectErr: false,
+		},
+		"hours and minutes": {
+			input:     "1h30m",
+			expectErr: false,
+		},
+		"zero": {
+			input:     "0s",
+			expectErr: true,
+		},
+		"negative": {
+			input:     "-5m",
+			expectErr: true,
+		},
+		"missing unit": {
+			input:     "30",
+			expectErr: true,
+		},
+		"empty string": {
+			input:     "",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateDuration(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validateIntAtLeast(t *testing.T) {
+	tcs := map[string]struct {
+		expectErr bool
+		input     int
+	}{
+		"greater": {
+			input:     30,
+			expectErr: false,
+		},
+		"equal": {
+			input:     1,
+			expectErr: false,
+		},
+		"less": {
+			input:     0,
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateIntAtLeast(1)(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validatePGPKey(t *testing.T) {
+	_, pgpKey := testPGPKey(t)
+
+	tcs := map[string]struct {
+		expectErr bool
+		input     string
+	}{
+		"base64 encoded key": {
+			input:     pgpKey,
+			expectErr: false,
+		},
+		"keybase reference": {
+			input:     "keybase:test",
+			expectErr: false,
+		},
+		"keybase reference without username": {
+			input:     "keybase:",
+			expectErr: true,
+		},
+		"not a key": {
+			input:     "dGVzdA==",
+			expectErr: true,
+		},
+		"empty string": {
+			input:     "",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validatePGPKey(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validateAbsolutePath(t *testing.T) {
+	tcs := map[string]struct {
+		expectErr bool
+		input     string
+	}{
+		"absolute path": {
+			input:     "/etc/consul.d",
+			expectErr: false,
+		},
+		"relative path": {
+			input:     "etc/consul.d",
+			expectErr: true,
+		},
+		"newline": {
+			input:     "/etc/consul.d\nExecStartPre=/bin/true",
+			expectErr: true,
+		},
+		"empty string": {
+			input:     "",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateAbsolutePath(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validateURL(t *testing.T) {
+	tcs := map[string]struct {
+		expectErr bool
+		input     string
+	}{
+		"https URL": {
+			input:     "https://apt.releases.hashicorp.com",
+			expectErr: false,
+		},
+		"http URL with path": {
+			input:     "http://mirror.example.com/hashicorp/apt",
+			expectErr: false,
+		},
+		"missing scheme": {
+			input:     "apt.releases.hashicorp.com",
+			expectErr: true,
+		},
+		"unsupported scheme": {
+			input:     "ftp://mirror.example.com",
+			expectErr: true,
+		},
+		"shell expansion": {
+			input:     "https://mirror.example.com/$(id)",
+			expectErr: true,
+		},
+		"empty string": {
+			input:     "",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateURL(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validatePackageVersion(t *testing.T) {
+	tcs := map[string]struct {
+		expectErr bool
+		input     string
+	}{
+		"version": {
+			input:     "1.9.5",
+			expectErr: false,
+		},
+		"version with revision": {
+			input:     "1.9.5-1",
+			expectErr: false,
+		},
+		"leading v": {
+			input:     "v1.9.5",
+			expectErr: true,
+		},
+		"shell expansion": {
+			input:     "1.9.5; reboot",
+			expectErr: true,
+		},
+		"empty string": {
+			input:     "",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validatePackageVersion(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validateHelmChartVersion(t *testing.T) {
+	tcs := map[string]struct {
+		expectErr bool
+		input     string
+	}{
+		"supported version": {
+			input:     "0.31.1",
+			expectErr: false,
+		},
+		"unsupported old version": {
+			input:     "0.20.0",
+			expectErr: true,
+		},
+		"unsupported new version": {
+			input:     "1.0.0",
+			expectErr: true,
+		},
+		"invalid version": {
+			input:     "latest",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateHelmChartVersion(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validateYAMLMap(t *testing.T) {
+	tcs := map[string]struct {
+		expectErr bool
+		input     string
+	}{
+		"map": {
+			input:     "client:\n  grpc: true\n",
+			expectErr: false,
+		},
+		"JSON map": {
+			input:     `{"client":{"grpc":true}}`,
+			expectErr: false,
+		},
+		"list": {
+			input:     "- client\n",
+			expectErr: true,
+		},
+		"invalid YAML": {
+			input:     "client: [",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateYAMLMap(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}
+
+func Test_validateHostPort(t *testing.T) {
+	tcs := map[string]struct {
+		expectErr bool
+		input     string
+	}{
+		"host name": {
+			input:     "aks.hcp.westus2.azmk8s.io",
+			expectErr: false,
+		},
+		"IP address with port": {
+			input:     "10.0.0.4:6443",
+			expectErr: false,
+		},
+		"IPv6 address with port": {
+			input:     "[fd00::4]:6443",
+			expectErr: false,
+		},
+		"scheme": {
+			input:     "https://10.0.0.4:6443",
+			expectErr: true,
+		},
+		"invalid port": {
+			input:     "10.0.0.4:https",
+			expectErr: true,
+		},
+		"empty string": {
+			input:     "",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range tcs {
+		t.Run(n, func(t *testing.T) {
+			r := require.New(t)
+
+			result := validateHostPort(tc.input, nil)
+			r.Equal(tc.expectErr, result.HasError())
+		})
+	}
+}